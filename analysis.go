@@ -0,0 +1,262 @@
+package path_mapper
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ConflictKind classifies a Conflict.
+type ConflictKind int
+
+const (
+	// Overlap means that some paths match two patterns, and the more specific one wins.
+	Overlap ConflictKind = iota + 1
+	// Shadowed means that a pattern never matches because an earlier pattern
+	// of the same shape matches every path it does.
+	Shadowed
+	// DuplicatePlaceholder means that a pattern uses the same placeholder name more than once.
+	DuplicatePlaceholder
+)
+
+func (k ConflictKind) String() string {
+	switch k {
+	case Overlap:
+		return "overlap"
+	case Shadowed:
+		return "shadowed"
+	case DuplicatePlaceholder:
+		return "duplicate placeholder"
+	}
+	return "unknown"
+}
+
+// Conflict is a problem found in the patterns of a PatternSet.
+type Conflict struct {
+	Kind ConflictKind
+	// Pattern is the pattern the conflict is reported for.
+	// For Overlap and Shadowed it is the pattern that loses to Other.
+	Pattern *Pattern
+	// Other is the pattern that wins over Pattern. It is nil for DuplicatePlaceholder.
+	Other *Pattern
+	// Example is a path that triggers the conflict.
+	Example string
+	// Reason explains the conflict.
+	Reason string
+}
+
+func (c Conflict) String() string {
+	return fmt.Sprintf("%v: %v", c.Kind, c.Reason)
+}
+
+// Analyze reports overlapping patterns, shadowed patterns and duplicate placeholder names
+// in the order the patterns were added.
+//
+// Two segments overlap if a value built from their examples matches both, such as "x.ba.txt" for "{a}.txt" and "x.{b}"
+// or "xy" for "{a:x.*}" and "{b:.*y}". Overlaps are found on a best-effort basis: constraints that only share values
+// unlike their examples, such as "abc" for "{a:a.c}" and "{b:.b.}", are not reported.
+func (s *PatternSet) Analyze() []Conflict {
+	var conflicts []Conflict
+	for i, e := range s.entries {
+		conflicts = append(conflicts, duplicatePlaceholders(e.pattern)...)
+		for _, earlier := range s.entries[:i] {
+			if c, ok := conflictBetween(earlier.pattern, e.pattern, &s.opts); ok {
+				conflicts = append(conflicts, c)
+			}
+		}
+	}
+	return conflicts
+}
+
+func duplicatePlaceholders(p *Pattern) []Conflict {
+	var conflicts []Conflict
+	seen := make(map[string]bool)
+	for _, s := range p.segments {
+		for _, name := range s.placeholders() {
+			if name == "" {
+				continue
+			}
+			if seen[name] {
+				conflicts = append(conflicts, Conflict{
+					Kind:    DuplicatePlaceholder,
+					Pattern: p,
+					Example: examplePath(p.segments, &p.opts),
+					Reason:  fmt.Sprintf("pattern(%v) uses placeholder %v more than once; only the last value is mapped", p, name),
+				})
+			}
+			seen[name] = true
+		}
+	}
+	return conflicts
+}
+
+// conflictBetween reports the conflict between earlier and later, if any.
+func conflictBetween(earlier, later *Pattern, o *options) (Conflict, bool) {
+	example, ok := overlapExample(earlier.segments, later.segments, o)
+	if !ok {
+		return Conflict{}, false
+	}
+	example = NormalizePath(example, o.normalization)
+
+	if sameShape(earlier, later, o) {
+		return Conflict{
+			Kind:    Shadowed,
+			Pattern: later,
+			Other:   earlier,
+			Example: example,
+			Reason:  fmt.Sprintf("pattern(%v) never matches because pattern(%v) was added earlier and matches the same paths, such as %v", later, earlier, example),
+		}, true
+	}
+
+	s := &PatternSet{opts: *o}
+	s.AddPattern(earlier, nil)
+	s.AddPattern(later, nil)
+	m, ok := s.Match(example)
+	winner, loser := earlier, later
+	if ok && m.Pattern == later {
+		winner, loser = later, earlier
+	}
+
+	reason := fmt.Sprintf("pattern(%v) and pattern(%v) both match %v; pattern(%v) wins because it was added earlier", earlier, later, example, winner)
+	for i := 0; i < len(winner.segments) && i < len(loser.segments); i++ {
+		w, l := winner.segments[i], loser.segments[i]
+		if w.kind != l.kind {
+			reason = fmt.Sprintf("pattern(%v) and pattern(%v) both match %v; pattern(%v) wins because %v is a %v where the other has the %v %v",
+				earlier, later, example, winner, w, w.kind, l.kind, l)
+			break
+		}
+		if w.kind == placeholderSegment && w.constraint != nil && l.constraint == nil {
+			reason = fmt.Sprintf("pattern(%v) and pattern(%v) both match %v; pattern(%v) wins because %v has a constraint where the other has %v",
+				earlier, later, example, winner, w, l)
+			break
+		}
+	}
+	return Conflict{
+		Kind:    Overlap,
+		Pattern: loser,
+		Other:   winner,
+		Example: example,
+		Reason:  reason,
+	}, true
+}
+
+func sameShape(a, b *Pattern, o *options) bool {
+	if len(a.segments) != len(b.segments) {
+		return false
+	}
+	for i := range a.segments {
+		if a.segments[i].kind != b.segments[i].kind || a.segments[i].sep != b.segments[i].sep {
+			return false
+		}
+		if a.segments[i].kind == literalSegment && !o.equalLiteral(a.segments[i].value, b.segments[i].value) {
+			return false
+		}
+		if a.segments[i].kind == mixedSegment && a.segments[i].mixedKey(o) != b.segments[i].mixedKey(o) {
+			return false
+		}
+		if a.segments[i].constraintKey() != b.segments[i].constraintKey() {
+			return false
+		}
+	}
+	return true
+}
+
+// overlapExample returns a path that matches both a and b, if there is one.
+func overlapExample(a, b []segment, o *options) (string, bool) {
+	var example strings.Builder
+	for i := 0; ; i++ {
+		if i == len(a) || i == len(b) {
+			if len(a) != len(b) {
+				return "", false
+			}
+			return example.String(), true
+		}
+
+		x, y := a[i], b[i]
+		if x.sep != y.sep {
+			return "", false
+		}
+		switch {
+		case x.kind == catchAllSegment:
+			return example.String() + examplePath(b[i:], o), true
+		case y.kind == catchAllSegment:
+			return example.String() + examplePath(a[i:], o), true
+		default:
+			text, ok := commonText(x, y, o)
+			if !ok {
+				return "", false
+			}
+			writeText(&example, x.sep, text, o)
+		}
+	}
+}
+
+// commonText returns a value that both segments match, trying the example of each and their concatenations.
+// A concatenation matches two segments of which one constrains the start of the value and the other its end,
+// such as "x.b" + "a.txt" for "{a}.txt" and "x.{b}", or "x" + "y" for "{a:x.*}" and "{b:.*y}".
+func commonText(x, y segment, o *options) (string, bool) {
+	ex, ey := exampleText(x), exampleText(y)
+	for _, text := range []string{ex, ey, ex + ey, ey + ex} {
+		if x.matchesText(text, o) && y.matchesText(text, o) {
+			return text, true
+		}
+	}
+	return "", false
+}
+
+// matchesText reports whether a segment other than a catch-all matches the decoded text of a segment.
+func (s *segment) matchesText(text string, o *options) bool {
+	switch s.kind {
+	case literalSegment:
+		return o.equalLiteral(s.value, text)
+	case mixedSegment:
+		return s.matchMixed(text, o, func(string) {})
+	}
+	return s.allows(text)
+}
+
+// examplePath returns a path that matches segments, using placeholder names as values.
+func examplePath(segments []segment, o *options) string {
+	var example strings.Builder
+	for _, s := range segments {
+		writeExample(&example, s, o)
+	}
+	return example.String()
+}
+
+// writeExample writes the separator and the example text of a segment,
+// escaped unless paths are matched WithoutDecoding.
+func writeExample(b *strings.Builder, s segment, o *options) {
+	writeText(b, s.sep, exampleText(s), o)
+}
+
+// writeText writes a separator, unless it is 0, and the text of a segment,
+// escaped unless paths are matched WithoutDecoding.
+func writeText(b *strings.Builder, sep byte, text string, o *options) {
+	if sep != 0 {
+		b.WriteByte(sep)
+	}
+	if o.noDecoding {
+		b.WriteString(text)
+	} else {
+		b.WriteString(escapeSegment(text, o.separators()))
+	}
+}
+
+// exampleText returns a value that matches the segment, using placeholder names as values
+// unless a constraint asks for something else.
+func exampleText(s segment) string {
+	switch s.kind {
+	case placeholderSegment:
+		if s.constraint != nil && s.constraint.example != "" {
+			return s.constraint.example
+		}
+		return s.value
+	case mixedSegment:
+		var b strings.Builder
+		for _, part := range s.parts {
+			b.WriteString(exampleText(part))
+		}
+		return b.String()
+	}
+	return s.value
+}
@@ -0,0 +1,243 @@
+package path_mapper
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPatternSet_Analyze(t *testing.T) {
+	type conflict struct {
+		Kind    ConflictKind
+		Pattern string
+		Other   string
+		Example string
+		Reason  string
+	}
+
+	tests := []struct {
+		name     string
+		patterns []string
+		opts     []Option
+		want     []conflict
+	}{
+		{
+			name: "Overlapping patterns",
+			patterns: []string{
+				"/{owner}/{repository}",
+				"/users/{name}",
+			},
+			want: []conflict{
+				{
+					Kind:    Overlap,
+					Pattern: "/{owner}/{repository}",
+					Other:   "/users/{name}",
+					Example: "/users/repository",
+					Reason:  "pattern(/{owner}/{repository}) and pattern(/users/{name}) both match /users/repository; pattern(/users/{name}) wins because users is a literal where the other has the placeholder {owner}",
+				},
+			},
+		},
+		{
+			name: "Catch-all overlaps with a longer pattern",
+			patterns: []string{
+				"/{owner}/{repository}/issues/{number}",
+				"/{owner}/{path...}",
+			},
+			want: []conflict{
+				{
+					Kind:    Overlap,
+					Pattern: "/{owner}/{path...}",
+					Other:   "/{owner}/{repository}/issues/{number}",
+					Example: "/owner/repository/issues/number",
+					Reason:  "pattern(/{owner}/{repository}/issues/{number}) and pattern(/{owner}/{path...}) both match /owner/repository/issues/number; pattern(/{owner}/{repository}/issues/{number}) wins because {repository} is a placeholder where the other has the catch-all {path...}",
+				},
+			},
+		},
+		{
+			name: "Constraints with a common value",
+			patterns: []string{
+				"/{a:x.*}",
+				"/{b:.*y}",
+			},
+			want: []conflict{
+				{
+					Kind:    Overlap,
+					Pattern: "/{b:.*y}",
+					Other:   "/{a:x.*}",
+					Example: "/xy",
+					Reason:  "pattern(/{a:x.*}) and pattern(/{b:.*y}) both match /xy; pattern(/{a:x.*}) wins because it was added earlier",
+				},
+			},
+		},
+		{
+			name: "Constraints without a common value",
+			patterns: []string{
+				"/{a:int}",
+				"/{b:[a-z]+}",
+			},
+		},
+		{
+			name: "Mixed segments with a common value",
+			patterns: []string{
+				"/{a}.txt",
+				"/x.{b}",
+			},
+			want: []conflict{
+				{
+					Kind:    Overlap,
+					Pattern: "/x.{b}",
+					Other:   "/{a}.txt",
+					Example: "/x.ba.txt",
+					Reason:  "pattern(/{a}.txt) and pattern(/x.{b}) both match /x.ba.txt; pattern(/{a}.txt) wins because it was added earlier",
+				},
+			},
+		},
+		{
+			name: "Mixed segments without a common value",
+			patterns: []string{
+				"/{a}.txt",
+				"/{b}.json",
+			},
+		},
+		{
+			name: "Constrained placeholder",
+			patterns: []string{
+				"/users/{name}",
+				"/users/{id:int}",
+				"/users/{id:uuid}",
+			},
+			want: []conflict{
+				{
+					Kind:    Overlap,
+					Pattern: "/users/{name}",
+					Other:   "/users/{id:int}",
+					Example: "/users/0",
+					Reason:  "pattern(/users/{name}) and pattern(/users/{id:int}) both match /users/0; pattern(/users/{id:int}) wins because {id:int} has a constraint where the other has {name}",
+				},
+				{
+					Kind:    Overlap,
+					Pattern: "/users/{name}",
+					Other:   "/users/{id:uuid}",
+					Example: "/users/aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa",
+					Reason:  "pattern(/users/{name}) and pattern(/users/{id:uuid}) both match /users/aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa; pattern(/users/{id:uuid}) wins because {id:uuid} has a constraint where the other has {name}",
+				},
+			},
+		},
+		{
+			name: "Shadowed pattern",
+			patterns: []string{
+				"/{owner}/{repository}/issues/{number}",
+				"/{user}/{project}/issues/{id}",
+			},
+			want: []conflict{
+				{
+					Kind:    Shadowed,
+					Pattern: "/{user}/{project}/issues/{id}",
+					Other:   "/{owner}/{repository}/issues/{number}",
+					Example: "/owner/repository/issues/number",
+					Reason:  "pattern(/{user}/{project}/issues/{id}) never matches because pattern(/{owner}/{repository}/issues/{number}) was added earlier and matches the same paths, such as /owner/repository/issues/number",
+				},
+			},
+		},
+		{
+			name: "Duplicate placeholder",
+			patterns: []string{
+				"/{id}/children/{id}",
+			},
+			want: []conflict{
+				{
+					Kind:    DuplicatePlaceholder,
+					Pattern: "/{id}/children/{id}",
+					Example: "/id/children/id",
+					Reason:  "pattern(/{id}/children/{id}) uses placeholder id more than once; only the last value is mapped",
+				},
+			},
+		},
+		{
+			name: "Literal with a percent sign",
+			patterns: []string{
+				"/100%/{x}",
+				"/100%/{y:int}",
+			},
+			want: []conflict{
+				{
+					Kind:    Overlap,
+					Pattern: "/100%/{x}",
+					Other:   "/100%/{y:int}",
+					Example: "/100%25/0",
+					Reason:  "pattern(/100%/{x}) and pattern(/100%/{y:int}) both match /100%25/0; pattern(/100%/{y:int}) wins because {y:int} has a constraint where the other has {x}",
+				},
+			},
+		},
+		{
+			name: "Empty segments with collapsed slashes",
+			patterns: []string{
+				"/a//{x}",
+				"/a//{y:int}",
+			},
+			opts: []Option{WithNormalization(CollapseSlashes)},
+			want: []conflict{
+				{
+					Kind:    Overlap,
+					Pattern: "/a//{y:int}",
+					Other:   "/a//{x}",
+					Example: "/a/0",
+					Reason:  "pattern(/a//{x}) and pattern(/a//{y:int}) both match /a/0; pattern(/a//{x}) wins because it was added earlier",
+				},
+			},
+		},
+		{
+			name: "Placeholder name with a percent sign",
+			patterns: []string{
+				"/{a%zz}",
+				"/{b...}",
+			},
+			want: []conflict{
+				{
+					Kind:    Overlap,
+					Pattern: "/{b...}",
+					Other:   "/{a%zz}",
+					Example: "/a%25zz",
+					Reason:  "pattern(/{a%zz}) and pattern(/{b...}) both match /a%25zz; pattern(/{a%zz}) wins because {a%zz} is a placeholder where the other has the catch-all {b...}",
+				},
+			},
+		},
+		{
+			name: "No conflicts",
+			patterns: []string{
+				"/{owner}/{repository}/issues/{number}",
+				"/{owner}/{repository}/pulls/{number}",
+				"/{owner}/{repository}",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewPatternSet(tt.opts...)
+			for _, pattern := range tt.patterns {
+				if err := s.Add(pattern, nil); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var got []conflict
+			for _, c := range s.Analyze() {
+				other := ""
+				if c.Other != nil {
+					other = c.Other.String()
+				}
+				got = append(got, conflict{
+					Kind:    c.Kind,
+					Pattern: c.Pattern.String(),
+					Other:   other,
+					Example: c.Example,
+					Reason:  c.Reason,
+				})
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Analyze() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package path_mapper
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Build generates the path or key that pattern maps into src. See Pattern.Build.
+//goland:noinspection GoUnusedExportedFunction
+func Build(pattern string, src interface{}, opts ...Option) (string, error) {
+	p, err := Compile(pattern, opts...)
+	if err != nil {
+		return "", err
+	}
+	return p.Build(src)
+}
+
+// Build generates the path or key that the pattern maps into src, which is a structure or a pointer to one.
+// Each placeholder is replaced with the field it is mapped into: strings as they are, integers in decimal,
+// and values whose type implements encoding.TextMarshaler with MarshalText.
+// Values are percent-encoded so that mapping the result gives them back,
+// unless the pattern was compiled WithoutDecoding, in which case a value containing a separator is an error.
+func (p *Pattern) Build(src interface{}) (string, error) {
+	return p.build(src, nil, nil, nil)
+}
+
+// build generates the path that the pattern maps into src.
+// The placeholders of a mixed segment are built one by one.
+// If escape is not nil, it replaces the percent-encoding of the values, and reports whether a value can be written.
+// If quote is not nil, it is applied to the literal text and the escaped values.
+// If wildcard is not nil, it replaces every placeholder without a value: a field that is missing,
+// nil or the zero value of its type. The segments that follow are built only if wildcard reports true.
+func (p *Pattern) build(src interface{}, escape func(value string, catchAll bool) (string, error), quote func(string) string, wildcard func(b *strings.Builder, s segment) bool) (string, error) {
+	v := reflect.Indirect(reflect.ValueOf(src))
+	if v.Kind() != reflect.Struct {
+		return "", errors.New("argument not a struct")
+	}
+	fields := fieldMapper.TypeMap(v.Type())
+
+	return p.buildValues(func(_ int, s segment) (string, bool, error) {
+		fi, ok := fields.Names[s.value]
+		if s.value == "" || !ok {
+			return "", true, ErrNoField
+		}
+		f, ok := fieldByIndexes(v, fi.Index)
+		if !ok {
+			return "", true, ErrNilField
+		}
+		value, err := formatValue(f)
+		return value, f.IsZero(), err
+	}, escape, quote, wildcard)
+}
+
+// BuildFunc is like Build, but takes the value of the i-th placeholder of the pattern from value
+// instead of the field it is mapped into. Code generated by pathmapper-gen builds paths with it.
+// value returns ErrNoField if the placeholder has no field and ErrNilField if its field is behind a nil pointer.
+func (p *Pattern) BuildFunc(value func(i int) (string, error)) (string, error) {
+	return p.buildValues(func(i int, _ segment) (string, bool, error) {
+		v, err := value(i)
+		return v, false, err
+	}, nil, nil, nil)
+}
+
+// ErrNoField is returned by the value function of BuildFunc for a placeholder that is mapped into no field.
+var ErrNoField = errors.New("no field")
+
+// ErrNilField is returned by the value function of BuildFunc for a placeholder whose field is behind a nil pointer.
+var ErrNilField = errors.New("nil field")
+
+// buildValues generates a path from the value of the i-th placeholder, which reports whether the value is a zero value.
+func (p *Pattern) buildValues(value func(i int, s segment) (string, bool, error), escape func(value string, catchAll bool) (string, error),
+	quote func(string) string, wildcard func(b *strings.Builder, s segment) bool) (string, error) {
+	if escape == nil {
+		escape = p.escape
+	}
+	if quote == nil {
+		quote = func(s string) string { return s }
+	}
+
+	var b strings.Builder
+	i := 0
+segments:
+	for _, s := range p.segments {
+		if s.sep != 0 {
+			b.WriteByte(s.sep)
+		}
+		parts := []segment{s}
+		if s.kind == mixedSegment {
+			parts = s.parts
+		}
+		for _, s := range parts {
+			if s.kind == literalSegment {
+				b.WriteString(quote(s.value))
+				continue
+			}
+
+			value, zero, err := value(i, s)
+			i++
+			if wildcard != nil && zero {
+				if !wildcard(&b, s) {
+					break segments
+				}
+				continue
+			}
+
+			switch {
+			case errors.Is(err, ErrNoField):
+				return "", fmt.Errorf("pattern(%v): no field for placeholder %v", p.raw, s)
+			case errors.Is(err, ErrNilField):
+				return "", fmt.Errorf("pattern(%v): field for placeholder %v is nil", p.raw, s)
+			case err != nil:
+				return "", fmt.Errorf("pattern(%v): placeholder %v: %w", p.raw, s, err)
+			}
+			if !s.allows(value) {
+				return "", fmt.Errorf("pattern(%v): %v does not satisfy placeholder %v", p.raw, value, s)
+			}
+			if value, err = escape(value, s.kind == catchAllSegment); err != nil {
+				return "", fmt.Errorf("pattern(%v): placeholder %v: %w", p.raw, s, err)
+			}
+			b.WriteString(quote(value))
+		}
+	}
+	return b.String(), nil
+}
+
+// rawValue writes a value as it is, which must not contain a separator unless it is the value of a catch-all.
+func (p *Pattern) rawValue(value string, catchAll bool) (string, error) {
+	if !catchAll && strings.ContainsAny(value, p.opts.separators()) {
+		return "", fmt.Errorf("%v contains a separator", value)
+	}
+	return value, nil
+}
+
+// escape escapes a value so that it is matched as a single segment, or as the rest of the path for a catch-all.
+func (p *Pattern) escape(value string, catchAll bool) (string, error) {
+	seps := p.opts.separators()
+	if p.opts.noDecoding {
+		if !catchAll && strings.ContainsAny(value, seps) {
+			return "", fmt.Errorf("%v contains a separator", value)
+		}
+		if strings.ContainsAny(value, p.opts.reserved) {
+			return "", fmt.Errorf("%v contains one of %v", value, p.opts.reserved)
+		}
+		return value, nil
+	}
+
+	if !catchAll {
+		return escapeSegment(value, seps), nil
+	}
+	parts, separators := splitSegments(value, seps, false)
+	var b strings.Builder
+	for i, part := range parts {
+		if separators[i] != 0 {
+			b.WriteByte(separators[i])
+		}
+		b.WriteString(escapeSegment(part, seps))
+	}
+	return b.String(), nil
+}
+
+func escapeSegment(s, seps string) string {
+	s = url.PathEscape(s)
+	if !strings.ContainsAny(s, seps) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(seps, s[i]) >= 0 {
+			_, _ = fmt.Fprintf(&b, "%%%02X", s[i])
+		} else {
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// fieldByIndexes returns the field given by the struct traversal, or false if it passes through a nil pointer.
+func fieldByIndexes(v reflect.Value, indexes []int) (reflect.Value, bool) {
+	for _, i := range indexes {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	return v, true
+}
+
+func formatValue(v reflect.Value) (string, error) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", errors.New("value is nil")
+		}
+		if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+			text, err := m.MarshalText()
+			return string(text), err
+		}
+		v = v.Elem()
+	}
+
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		text, err := m.MarshalText()
+		return string(text), err
+	}
+	if v.CanAddr() {
+		if m, ok := v.Addr().Interface().(encoding.TextMarshaler); ok {
+			text, err := m.MarshalText()
+			return string(text), err
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	}
+	return "", fmt.Errorf("unsupported conversion. Value type %v into string", v.Type())
+}
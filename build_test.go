@@ -0,0 +1,237 @@
+package path_mapper
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type S3Object struct {
+	Bucket string
+	Key    string
+}
+
+type CacheKey struct {
+	ID int `alias:"id"`
+}
+
+func TestWithSeparators(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		seps    string
+		path    string
+		dest    interface{}
+		want    interface{}
+		success bool
+	}{
+		{
+			name:    "Redis key",
+			pattern: "user:{id}:profile",
+			seps:    ":",
+			path:    "user:42:profile",
+			dest:    &CacheKey{},
+			want:    &CacheKey{ID: 42},
+			success: true,
+		},
+		{
+			name:    "Slash is not a separator",
+			pattern: "user:{id}:profile",
+			seps:    ":",
+			path:    "user:4/2:profile",
+			dest:    &CacheKey{},
+			success: false,
+		},
+		{
+			name:    "ARN",
+			pattern: "arn:aws:s3:::{bucket}/{key...}",
+			seps:    ":/",
+			path:    "arn:aws:s3:::logs/2024/01/app.log",
+			dest:    &S3Object{},
+			want:    &S3Object{Bucket: "logs", Key: "2024/01/app.log"},
+			success: true,
+		},
+		{
+			name:    "Separators must be the same as the pattern's",
+			pattern: "arn:aws:s3:::{bucket}/{key...}",
+			seps:    ":/",
+			path:    "arn:aws:s3:::logs:2024/01/app.log",
+			dest:    &S3Object{},
+			success: false,
+		},
+		{
+			name:    "Invalid separator",
+			pattern: "user:{id}",
+			seps:    "{",
+			path:    "user:42",
+			dest:    &CacheKey{},
+			success: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Mapping(tt.pattern, tt.path, tt.dest, WithSeparators(tt.seps))
+			if (err == nil) != tt.success {
+				t.Fatalf("Mapping() return (%v), which is not what we want.", err)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, tt.dest); diff != "" {
+				t.Errorf("Mapping() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPatternSet_Separators(t *testing.T) {
+	s := NewPatternSet(WithSeparators(":/"))
+	_ = s.Add("arn:aws:s3:::{bucket}/{key...}", "object")
+	_ = s.Add("arn:aws:s3:::{bucket}", "bucket")
+
+	for path, want := range map[string]interface{}{
+		"arn:aws:s3:::logs/2024/01/app.log": "object",
+		"arn:aws:s3:::logs":                 "bucket",
+		"arn/aws/s3/::logs":                 nil,
+	} {
+		m, ok := s.Match(path)
+		if want == nil {
+			if ok {
+				t.Errorf("Match(%v) matched %v", path, m.Value)
+			}
+			continue
+		}
+		if !ok || m.Value != want {
+			t.Errorf("Match(%v) = %v, want %v", path, m, want)
+		}
+	}
+}
+
+type Amount int
+
+func (a Amount) MarshalText() ([]byte, error) {
+	return []byte("$" + string(rune('0'+int(a)))), nil
+}
+
+type BuildValues struct {
+	Values
+	Amount Amount
+	Ptr    *int
+}
+
+func TestPattern_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		opts    []Option
+		src     interface{}
+		want    string
+		success bool
+	}{
+		{
+			name:    "Path",
+			pattern: "/{owner}/{repository}/issues/{number}",
+			src:     GitHubIssue{Owner: "KamikazeZirou", Repository: "path-mapper", Number: 1},
+			want:    "/KamikazeZirou/path-mapper/issues/1",
+			success: true,
+		},
+		{
+			name:    "Values are escaped",
+			pattern: "/{owner}/{repository}",
+			src:     &GitHubIssue{Owner: "John Doe", Repository: "a/b%"},
+			want:    "/John%20Doe/a%2Fb%25",
+			success: true,
+		},
+		{
+			name:    "Catch-all keeps its separators",
+			pattern: "arn:aws:s3:::{bucket}/{key...}",
+			opts:    []Option{WithSeparators(":/")},
+			src:     S3Object{Bucket: "logs", Key: "2024/my app:1.log"},
+			want:    "arn:aws:s3:::logs/2024/my%20app:1.log",
+			success: true,
+		},
+		{
+			name:    "Kinds",
+			pattern: "/{int}/{int8}/{uint64}/{str}/{amount}/{ptr}",
+			src:     BuildValues{Values: Values{Int: -1, Int8: 2, Uint64: 3, Str: "abc"}, Amount: 5, Ptr: intAddr(6)},
+			want:    "/-1/2/3/abc/$5/6",
+			success: true,
+		},
+		{
+			name:    "Without decoding",
+			pattern: "user:{id}:profile",
+			opts:    []Option{WithSeparators(":"), WithoutDecoding()},
+			src:     CacheKey{ID: 42},
+			want:    "user:42:profile",
+			success: true,
+		},
+		{
+			name:    "Mixed segment",
+			pattern: "/files/{name}.{ext}",
+			src:     FileName{Name: "my notes", Ext: "txt"},
+			want:    "/files/my%20notes.txt",
+			success: true,
+		},
+		{
+			name:    "Value does not satisfy the constraint",
+			pattern: "/{owner:[a-z]+}",
+			src:     GitHubIssue{Owner: "Guest"},
+			success: false,
+		},
+		{
+			name:    "Separator in a value without decoding",
+			pattern: "/{owner}",
+			opts:    []Option{WithoutDecoding()},
+			src:     GitHubIssue{Owner: "a/b"},
+			success: false,
+		},
+		{
+			name:    "No field",
+			pattern: "/{owner}/{missing}",
+			src:     GitHubIssue{},
+			success: false,
+		},
+		{
+			name:    "Nil pointer",
+			pattern: "/{ptr}",
+			src:     BuildValues{},
+			success: false,
+		},
+		{
+			name:    "Not a struct",
+			pattern: "/{owner}",
+			src:     "owner",
+			success: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MustCompile(tt.pattern, tt.opts...).Build(tt.src)
+			if (err == nil) != tt.success {
+				t.Fatalf("Build() return (%v), which is not what we want.", err)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPattern_Build_RoundTrip(t *testing.T) {
+	p := MustCompile("arn:aws:s3:::{bucket}/{key...}", WithSeparators(":/"))
+	want := &S3Object{Bucket: "my:bucket", Key: "2024/100%/app.log"}
+
+	path, err := p.Build(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := &S3Object{}
+	if err := p.Mapping(path, got); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Mapping(Build()) mismatch (-want +got):\n%s", diff)
+	}
+}
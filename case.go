@@ -0,0 +1,188 @@
+package path_mapper
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// LiteralCase decides how the case of literal segments is compared.
+// Captured placeholder values always keep their original case.
+type LiteralCase int
+
+const (
+	// CaseSensitive compares literal segments exactly.
+	CaseSensitive LiteralCase = iota
+	// IgnoreASCIICase treats the ASCII letters A-Z and a-z as equal, leaving other characters case-sensitive.
+	IgnoreASCIICase
+	// FoldCase compares literal segments under Unicode simple case folding, as strings.EqualFold does.
+	FoldCase
+)
+
+// WithLiteralCase sets how the case of literal segments is compared.
+func WithLiteralCase(c LiteralCase) Option {
+	return func(o *options) {
+		o.literalCase = c
+	}
+}
+
+// equalLiteral reports whether a literal segment of a pattern and a path segment are equal.
+func (o *options) equalLiteral(literal, s string) bool {
+	switch o.literalCase {
+	case IgnoreASCIICase:
+		return asciiEqualFold(literal, s)
+	case FoldCase:
+		return strings.EqualFold(literal, s)
+	}
+	return literal == s
+}
+
+// indexLiteral returns the index of the first occurrence of literal in s, or -1.
+func (o *options) indexLiteral(s, literal string) int {
+	if o.literalCase == CaseSensitive {
+		return strings.Index(s, literal)
+	}
+	for i := 0; i < len(s); i++ {
+		if o.literalPrefix(s[i:], literal) >= 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// literalPrefix returns the length of the prefix of s that equals literal, or -1 if there is none.
+// Under FoldCase the length may differ from that of literal, since "k" matches the Kelvin sign, which takes three bytes.
+func (o *options) literalPrefix(s, literal string) int {
+	if o.literalCase != FoldCase {
+		if len(s) < len(literal) || !o.equalLiteral(literal, s[:len(literal)]) {
+			return -1
+		}
+		return len(literal)
+	}
+	n := 0
+	for _, r := range literal {
+		if n == len(s) {
+			return -1
+		}
+		c, size := utf8.DecodeRuneInString(s[n:])
+		if !equalFoldRune(r, c) {
+			return -1
+		}
+		n += size
+	}
+	return n
+}
+
+// literalSuffix returns the length of the suffix of s that equals literal, or -1 if there is none.
+func (o *options) literalSuffix(s, literal string) int {
+	if o.literalCase != FoldCase {
+		if len(s) < len(literal) || !o.equalLiteral(literal, s[len(s)-len(literal):]) {
+			return -1
+		}
+		return len(literal)
+	}
+	n := 0
+	for literal != "" {
+		if n == len(s) {
+			return -1
+		}
+		r, rsize := utf8.DecodeLastRuneInString(literal)
+		c, size := utf8.DecodeLastRuneInString(s[:len(s)-n])
+		if !equalFoldRune(r, c) {
+			return -1
+		}
+		literal = literal[:len(literal)-rsize]
+		n += size
+	}
+	return n
+}
+
+// equalFoldRune reports whether r and c are equal under Unicode simple case folding.
+func equalFoldRune(r, c rune) bool {
+	if r == c {
+		return true
+	}
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f == c {
+			return true
+		}
+	}
+	return false
+}
+
+// literalKey returns the form in which literal segments that compare equal are identical.
+// It returns s as is, without allocating, if s is already in that form.
+func (o *options) literalKey(s string) string {
+	switch o.literalCase {
+	case IgnoreASCIICase:
+		return asciiLower(s)
+	case FoldCase:
+		return foldCase(s)
+	}
+	return s
+}
+
+func asciiEqualFold(a, b string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := 0; i < len(a); i++ {
+		x, y := a[i], b[i]
+		if 'A' <= x && x <= 'Z' {
+			x += 'a' - 'A'
+		}
+		if 'A' <= y && y <= 'Z' {
+			y += 'a' - 'A'
+		}
+		if x != y {
+			return false
+		}
+	}
+	return true
+}
+
+func asciiLower(s string) string {
+	i := strings.IndexFunc(s, func(r rune) bool { return 'A' <= r && r <= 'Z' })
+	if i < 0 {
+		return s
+	}
+	b := []byte(s)
+	for ; i < len(b); i++ {
+		if 'A' <= b[i] && b[i] <= 'Z' {
+			b[i] += 'a' - 'A'
+		}
+	}
+	return string(b)
+}
+
+// foldCase replaces every rune of s with the smallest rune that is equal to it under simple case folding.
+func foldCase(s string) string {
+	i := strings.IndexFunc(s, func(r rune) bool { return foldRune(r) != r })
+	if i < 0 {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	b.WriteString(s[:i])
+	for i < len(s) {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			// Keep invalid bytes as they are so that they only equal themselves.
+			b.WriteByte(s[i])
+		} else {
+			b.WriteRune(foldRune(r))
+		}
+		i += size
+	}
+	return b.String()
+}
+
+func foldRune(r rune) rune {
+	min := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < min {
+			min = f
+		}
+	}
+	return min
+}
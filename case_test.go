@@ -0,0 +1,122 @@
+package path_mapper
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWithLiteralCase(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		path    string
+		c       LiteralCase
+		want    *GitHubIssue
+		success bool
+	}{
+		{
+			name:    "Case-sensitive",
+			pattern: "/repos/{owner}/{repository}/issues/{number}",
+			path:    "/Repos/KamikazeZirou/path-mapper/Issues/1",
+			c:       CaseSensitive,
+			success: false,
+		},
+		{
+			name:    "ASCII case is ignored and captured values keep their case",
+			pattern: "/repos/{owner}/{repository}/issues/{number}",
+			path:    "/Repos/KamikazeZirou/path-mapper/ISSUES/1",
+			c:       IgnoreASCIICase,
+			want:    &GitHubIssue{Owner: "KamikazeZirou", Repository: "path-mapper", Number: 1},
+			success: true,
+		},
+		{
+			name:    "Non-ASCII case is not ignored without folding",
+			pattern: "/äpfel/{owner}/{repository}/issues/{number}",
+			path:    "/ÄPFEL/KamikazeZirou/path-mapper/issues/1",
+			c:       IgnoreASCIICase,
+			success: false,
+		},
+		{
+			name:    "Unicode case folding",
+			pattern: "/ΣΟΦΙΑ/{owner}/{repository}/issues/{number}",
+			path:    "/σοφια/KamikazeZirou/path-mapper/issues/1",
+			c:       FoldCase,
+			want:    &GitHubIssue{Owner: "KamikazeZirou", Repository: "path-mapper", Number: 1},
+			success: true,
+		},
+		{
+			name:    "Unicode case folding of a suffix with a longer rune",
+			pattern: "/{owner}.k/{repository}/issues/{number}",
+			path:    "/KamikazeZirou.\u212A/path-mapper/issues/1",
+			c:       FoldCase,
+			want:    &GitHubIssue{Owner: "KamikazeZirou", Repository: "path-mapper", Number: 1},
+			success: true,
+		},
+		{
+			name:    "Unicode case folding of a prefix with a shorter rune",
+			pattern: "/\u212A.{owner}/{repository}/issues/{number}",
+			path:    "/k.KamikazeZirou/path-mapper/issues/1",
+			c:       FoldCase,
+			want:    &GitHubIssue{Owner: "KamikazeZirou", Repository: "path-mapper", Number: 1},
+			success: true,
+		},
+		{
+			name:    "Unicode case folding between placeholders",
+			pattern: "/{owner}-k-{repository}/issues/{number}",
+			path:    "/KamikazeZirou-\u212A-path-mapper/issues/1",
+			c:       FoldCase,
+			want:    &GitHubIssue{Owner: "KamikazeZirou", Repository: "path-mapper", Number: 1},
+			success: true,
+		},
+		{
+			name:    "Longer rune is not folded without folding",
+			pattern: "/{owner}.k/{repository}/issues/{number}",
+			path:    "/KamikazeZirou.\u212A/path-mapper/issues/1",
+			c:       IgnoreASCIICase,
+			success: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, match := range []func(dest *GitHubIssue) error{
+				func(dest *GitHubIssue) error {
+					return Mapping(tt.pattern, tt.path, dest, WithLiteralCase(tt.c))
+				},
+				func(dest *GitHubIssue) error {
+					s := NewPatternSet(WithLiteralCase(tt.c))
+					_ = s.Add(tt.pattern, nil)
+					m, ok := s.Match(tt.path)
+					if !ok {
+						return &MismatchError{Pattern: tt.pattern, Path: tt.path}
+					}
+					return m.Mapping(dest)
+				},
+			} {
+				got := &GitHubIssue{}
+				err := match(got)
+				if (err == nil) != tt.success {
+					t.Fatalf("return (%v), which is not what we want.", err)
+				}
+				if err != nil {
+					continue
+				}
+				if diff := cmp.Diff(tt.want, got); diff != "" {
+					t.Errorf("mismatch (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestPatternSet_Analyze_LiteralCase(t *testing.T) {
+	s := NewPatternSet(WithLiteralCase(IgnoreASCIICase))
+	_ = s.Add("/repos/{owner}", nil)
+	_ = s.Add("/Repos/{name}", nil)
+
+	conflicts := s.Analyze()
+	if len(conflicts) != 1 || conflicts[0].Kind != Shadowed {
+		t.Errorf("Analyze() = %v, want a shadowed pattern", conflicts)
+	}
+}
@@ -0,0 +1,325 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+
+	mapper "github.com/KamikazeZirou/path-mapper"
+	"github.com/KamikazeZirou/path-mapper/internal/typesx"
+)
+
+const directive = "//pathmapper:pattern "
+
+// mapperPath is the import path of the package whose Pattern the generated code uses.
+const mapperPath = "github.com/KamikazeZirou/path-mapper"
+
+// generate returns the source of the methods of every annotated structure type in pkg.
+func generate(pkg *loadedPackage) ([]byte, error) {
+	g := &generator{pkg: pkg.types, imports: make(map[string]string), used: make(map[string]bool)}
+	for _, file := range pkg.files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				spec := spec.(*ast.TypeSpec)
+				doc := spec.Doc
+				if doc == nil && len(gen.Specs) == 1 {
+					doc = gen.Doc
+				}
+				pattern, ok, err := patternOf(doc)
+				if err != nil {
+					return nil, fmt.Errorf("%v: %w", pkg.fset.Position(spec.Pos()), err)
+				}
+				if !ok {
+					continue
+				}
+				if err := g.typ(spec.Name.Name, pattern); err != nil {
+					return nil, fmt.Errorf("%v: type %v: %w", pkg.fset.Position(spec.Pos()), spec.Name.Name, err)
+				}
+			}
+		}
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by pathmapper-gen. DO NOT EDIT.\n\npackage %v\n\nimport (\n", pkg.types.Name())
+	names := make([]string, 0, len(g.imports))
+	for name := range g.imports {
+		names = append(names, name)
+	}
+	// Standard packages come first, as goimports groups them.
+	std := func(path string) bool { return !strings.Contains(strings.SplitN(path, "/", 2)[0], ".") }
+	sort.Slice(names, func(i, j int) bool {
+		a, b := g.imports[names[i]], g.imports[names[j]]
+		if std(a) != std(b) {
+			return std(a)
+		}
+		return a < b
+	})
+	for i, name := range names {
+		path := g.imports[name]
+		if !g.used[name] {
+			continue
+		}
+		if i > 0 && std(g.imports[names[i-1]]) && !std(path) {
+			b.WriteString("\n")
+		}
+		if name != path[strings.LastIndexByte(path, '/')+1:] {
+			fmt.Fprintf(&b, "\t%v %q\n", name, path)
+		} else {
+			fmt.Fprintf(&b, "\t%q\n", path)
+		}
+	}
+	b.WriteString(")\n")
+	b.Write(g.buf.Bytes())
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated invalid code: %w\n%s", err, b.Bytes())
+	}
+	return src, nil
+}
+
+// patternOf returns the pattern of a //pathmapper:pattern directive in doc.
+func patternOf(doc *ast.CommentGroup) (string, bool, error) {
+	if doc == nil {
+		return "", false, nil
+	}
+	for _, c := range doc.List {
+		if !strings.HasPrefix(c.Text, directive) {
+			continue
+		}
+		pattern, err := strconv.Unquote(strings.TrimSpace(strings.TrimPrefix(c.Text, directive)))
+		if err != nil {
+			return "", false, fmt.Errorf("pathmapper:pattern needs a quoted pattern: %v", c.Text)
+		}
+		return pattern, true, nil
+	}
+	return "", false, nil
+}
+
+type generator struct {
+	pkg *types.Package
+	buf bytes.Buffer
+	// imports maps the names of imported packages to their paths, and used tells which of them the code refers to.
+	imports map[string]string
+	used    map[string]bool
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// use marks an import as used and returns its name.
+func (g *generator) use(name, path string) string {
+	g.imports[name] = path
+	g.used[name] = true
+	return name
+}
+
+// typeString writes t as the generated code refers to it, importing the packages of named types.
+func (g *generator) typeString(t types.Type) string {
+	return types.TypeString(t, func(p *types.Package) string {
+		if p == g.pkg {
+			return ""
+		}
+		return g.use(p.Name(), p.Path())
+	})
+}
+
+// typ generates the methods of the structure type name.
+func (g *generator) typ(name, pattern string) error {
+	p, err := mapper.Compile(pattern)
+	if err != nil {
+		return err
+	}
+	obj := g.pkg.Scope().Lookup(name)
+	if obj == nil {
+		return fmt.Errorf("cannot be resolved")
+	}
+	if _, ok := obj.Type().Underlying().(*types.Struct); !ok {
+		return fmt.Errorf("not a struct")
+	}
+	if named, ok := obj.Type().(*types.Named); ok && named.TypeParams().Len() > 0 {
+		return fmt.Errorf("generic types are not supported")
+	}
+	fields := typesx.Fields(obj.Type())
+
+	placeholders := p.Names()
+	traversals := make([][]*types.Var, len(placeholders))
+	for i, placeholder := range placeholders {
+		f, ok := fields[placeholder]
+		if !ok || placeholder == "" {
+			continue
+		}
+		for _, v := range f {
+			if !v.Exported() && v.Pkg() != g.pkg {
+				return fmt.Errorf("field %v of placeholder %v is not accessible", v.Name(), placeholder)
+			}
+		}
+		if t := f[len(f)-1].Type(); !typesx.Convertible(t) || !typesx.Formattable(t) {
+			return fmt.Errorf("field of placeholder %v has type %v, which cannot hold a path parameter", placeholder, t)
+		}
+		traversals[i] = f
+	}
+
+	patternVar := "_" + name + "_pattern"
+	g.printf("\nvar %v = %v.MustCompile(%q)\n", patternVar, g.use("mapper", mapperPath), pattern)
+
+	g.printf("\n// MapPath maps path into v as Pattern.Mapping does with the pattern %q.\n", pattern)
+	g.printf("func (v *%v) MapPath(path string) error {\n", name)
+	values := "_"
+	for _, f := range traversals {
+		if f != nil {
+			values = "values"
+		}
+	}
+	g.printf("%v, ok := %v.Values(path)\n", values, patternVar)
+	g.printf("if !ok {\nreturn &mapper.MismatchError{Pattern: %v.String(), Path: path}\n}\n", patternVar)
+	// Like the reflective mapping, allocate the pointers and maps on the way to every field before assigning any.
+	allocated := make(map[string]bool)
+	for _, f := range traversals {
+		expr := "v"
+		for _, v := range f {
+			expr += "." + v.Name()
+			if allocated[expr] {
+				continue
+			}
+			allocated[expr] = true
+			switch t := v.Type().Underlying().(type) {
+			case *types.Pointer:
+				g.printf("if %v == nil {\n%v = new(%v)\n}\n", expr, expr, g.typeString(t.Elem()))
+			case *types.Map:
+				g.printf("if %v == nil {\n%v = make(%v)\n}\n", expr, expr, g.typeString(v.Type()))
+			}
+		}
+	}
+	for i, f := range traversals {
+		if f == nil {
+			continue
+		}
+		value := fmt.Sprintf("values[%d]", i)
+		bindErr := fmt.Sprintf("&mapper.BindError{Name: %q, Value: %v, Err: %%v}", placeholders[i], value)
+		// A pointer field has been allocated above, and the value is assigned through it.
+		g.assign(fieldExpr(f), f[len(f)-1].Type(), value, bindErr, true)
+	}
+	g.printf("return nil\n}\n")
+
+	g.printf("\n// BuildPath generates the path that the pattern %q maps into v, as Pattern.Build does.\n", pattern)
+	g.printf("func (v *%v) BuildPath() (string, error) {\n", name)
+	if values == "_" {
+		g.printf("return %v.BuildFunc(func(int) (string, error) {\nreturn \"\", mapper.ErrNoField\n})\n}\n", patternVar)
+		return nil
+	}
+	g.printf("return %v.BuildFunc(func(i int) (string, error) {\nswitch i {\n", patternVar)
+	for i, f := range traversals {
+		if f == nil {
+			continue
+		}
+		g.printf("case %d:\n", i)
+		expr := "v"
+		for _, v := range f[:len(f)-1] {
+			expr += "." + v.Name()
+			if _, ok := v.Type().Underlying().(*types.Pointer); ok {
+				g.printf("if %v == nil {\nreturn \"\", mapper.ErrNilField\n}\n", expr)
+			}
+		}
+		g.format(fieldExpr(f), f[len(f)-1].Type())
+	}
+	g.printf("}\nreturn \"\", mapper.ErrNoField\n})\n}\n")
+	return nil
+}
+
+func fieldExpr(f []*types.Var) string {
+	expr := "v"
+	for _, v := range f {
+		expr += "." + v.Name()
+	}
+	return expr
+}
+
+// operand parenthesizes a dereference so that a selector applies to the dereferenced value.
+func operand(expr string) string {
+	if strings.HasPrefix(expr, "*") {
+		return "(" + expr + ")"
+	}
+	return expr
+}
+
+// assign writes the code that converts value into lhs of type t as convertAssign does.
+// t must be convertible, and bindErr formats the error returned for a value that cannot be converted.
+// If allocated is true, lhs is a pointer that has already been allocated.
+func (g *generator) assign(lhs string, t types.Type, value, bindErr string, allocated bool) {
+	fail := func(err string) {
+		g.printf("return "+bindErr+"\n", err)
+	}
+
+	if types.Implements(types.NewPointer(t), typesx.ParserType) {
+		g.printf("if parsed, err := %v.Parse(%v); err != nil {\n", operand(lhs), value)
+		fail(g.use("fmt", "fmt") + `.Errorf(": %w", err)`)
+		g.printf("} else {\n%v = parsed.(%v)\n}\n", lhs, g.typeString(t))
+		return
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Pointer:
+		if !allocated {
+			g.printf("%v = new(%v)\n", lhs, g.typeString(u.Elem()))
+		}
+		g.assign("*"+lhs, u.Elem(), value, bindErr, false)
+	case *types.Basic:
+		if u.Info()&types.IsString != 0 {
+			if _, isBasic := t.(*types.Basic); isBasic {
+				g.printf("%v = %v\n", lhs, value)
+			} else {
+				g.printf("%v = %v(%v)\n", lhs, g.typeString(t), value)
+			}
+			return
+		}
+		parse, kind := "ParseInt", "int"
+		if u.Info()&types.IsUnsigned != 0 {
+			parse, kind = "ParseUint", "uint"
+		}
+		g.printf("if n, err := %v.%v(%v, 10, 0); err != nil {\n", g.use("strconv", "strconv"), parse, value)
+		fail(fmt.Sprintf("%v.Errorf(\"%%v is invalid as %v\", %v)", g.use("fmt", "fmt"), kind, value))
+		g.printf("} else {\n%v = %v(n)\n}\n", lhs, g.typeString(t))
+	}
+}
+
+// format writes the code that returns expr of the formattable type t as a string as formatValue does.
+func (g *generator) format(expr string, t types.Type) {
+	if p, ok := t.Underlying().(*types.Pointer); ok {
+		g.printf("if %v == nil {\nreturn \"\", %v.New(\"value is nil\")\n}\n", expr, g.use("errors", "errors"))
+		if types.Implements(t, typesx.TextMarshalerType) {
+			g.printf("text, err := %v.MarshalText()\nreturn string(text), err\n", expr)
+			return
+		}
+		expr, t = "*"+expr, p.Elem()
+	}
+
+	if types.Implements(t, typesx.TextMarshalerType) || types.Implements(types.NewPointer(t), typesx.TextMarshalerType) {
+		g.printf("text, err := %v.MarshalText()\nreturn string(text), err\n", operand(expr))
+		return
+	}
+	u := t.Underlying().(*types.Basic)
+	switch {
+	case u.Info()&types.IsString != 0:
+		if _, isBasic := t.(*types.Basic); isBasic {
+			g.printf("return %v, nil\n", expr)
+		} else {
+			g.printf("return string(%v), nil\n", expr)
+		}
+	case u.Info()&types.IsUnsigned != 0:
+		g.printf("return %v.FormatUint(uint64(%v), 10), nil\n", g.use("strconv", "strconv"), expr)
+	default:
+		g.printf("return %v.FormatInt(int64(%v), 10), nil\n", g.use("strconv", "strconv"), expr)
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGenerate(t *testing.T) {
+	dir := filepath.Join("internal", "example")
+	output := filepath.Join(dir, "pathmapper_gen.go")
+	want, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkg, err := load(dir, output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := generate(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(string(want), string(got)); diff != "" {
+		t.Errorf("generate() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{name: "Unquoted pattern", src: "//pathmapper:pattern /{id}\ntype T struct{ ID string }"},
+		{name: "Invalid pattern", src: "//pathmapper:pattern \"/{id\"\ntype T struct{ ID string }"},
+		{name: "Not a struct", src: "//pathmapper:pattern \"/{id}\"\ntype T string"},
+		{name: "Unsupported field type", src: "//pathmapper:pattern \"/{ratio}\"\ntype T struct{ Ratio float64 }"},
+		{name: "Generic type", src: "//pathmapper:pattern \"/{id}\"\ntype T[E any] struct{ ID string }"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "t.go"), []byte("package t\n\n"+tt.src+"\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			pkg, err := load(dir, filepath.Join(dir, "pathmapper_gen.go"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := generate(pkg); err == nil || !strings.Contains(err.Error(), "t.go") {
+				t.Errorf("generate() return (%v), which is not what we want.", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// Package example holds structures with generated mappers, which are tested against the reflective mapping.
+package example
+
+import (
+	"errors"
+	"strings"
+)
+
+//go:generate go run github.com/KamikazeZirou/path-mapper/cmd/pathmapper-gen
+
+//pathmapper:pattern "/{owner}/{repository}/issues/{number:int}"
+type GitHubIssue struct {
+	Owner      string
+	Repository string
+	Number     int
+}
+
+// Kind is a kind of issue.
+type Kind string
+
+// Page is a page number.
+type Page uint16
+
+// Base holds the fields that several structures share.
+type Base struct {
+	Owner string
+	Page  *Page
+}
+
+// Author is the author of a file.
+type Author struct {
+	Name string
+}
+
+// Slug is parsed from and formatted into its lowercased text.
+type Slug struct {
+	text string
+}
+
+// Parse parses a nonempty slug.
+func (s *Slug) Parse(text string) (interface{}, error) {
+	if text == "" {
+		return nil, errors.New("empty slug")
+	}
+	return Slug{text: strings.ToLower(text)}, nil
+}
+
+// MarshalText returns the text of the slug.
+func (s Slug) MarshalText() ([]byte, error) {
+	return []byte(s.text), nil
+}
+
+//pathmapper:pattern "/{owner}/{kind:issues|pulls}/p{page}-{offset}/{slug}/{author.name}/{small}/{missing}/{path...}"
+type Listing struct {
+	*Base
+	Kind   Kind
+	Offset *int64 `alias:"offset"`
+	Slug   Slug
+	Author Author
+	Small  int8
+	Path   string `alias:"path"`
+}
+
+//pathmapper:pattern "/static/{...}"
+type Static struct{}
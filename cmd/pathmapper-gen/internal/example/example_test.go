@@ -0,0 +1,106 @@
+package example
+
+import (
+	"fmt"
+	"testing"
+
+	mapper "github.com/KamikazeZirou/path-mapper"
+	"github.com/google/go-cmp/cmp"
+)
+
+// errorString describes an error by its type and text, so that the errors of both mappers can be compared.
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return fmt.Sprintf("%T: %v", err, err)
+}
+
+func TestListing_MapPath(t *testing.T) {
+	p := mapper.MustCompile("/{owner}/{kind:issues|pulls}/p{page}-{offset}/{slug}/{author.name}/{small}/{missing}/{path...}")
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{name: "Matched", path: "/a/issues/p2-3/Hello/bob/4/x/b/c"},
+		{name: "Negative offset", path: "/a/pulls/p2--3/hello/bob/-4/x/"},
+		{name: "Mismatched kind", path: "/a/commits/p2-3/hello/bob/4/x/b"},
+		{name: "Invalid page", path: "/a/issues/p-2-3/hello/bob/4/x/b"},
+		{name: "Invalid offset", path: "/a/issues/p2-a/hello/bob/4/x/b"},
+		{name: "Empty slug", path: "/a/issues/p2-3//bob/4/x/b"},
+		{name: "Invalid small", path: "/a/issues/p2-3/hello/bob/b/x/b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var want Listing
+			wantErr := p.Mapping(tt.path, &want)
+			var got Listing
+			err := got.MapPath(tt.path)
+			if diff := cmp.Diff(errorString(wantErr), errorString(err)); diff != "" {
+				t.Errorf("MapPath() error mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(want, got, cmp.AllowUnexported(Slug{})); diff != "" {
+				t.Errorf("MapPath() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestListing_BuildPath(t *testing.T) {
+	p := mapper.MustCompile("/{owner}/{kind:issues|pulls}/p{page}-{offset}/{slug}/{author.name}/{small}/{missing}/{path...}")
+	page := Page(2)
+	offset := int64(-3)
+
+	tests := []struct {
+		name string
+		v    Listing
+	}{
+		{name: "Complete", v: Listing{Base: &Base{Owner: "a", Page: &page}, Kind: "issues", Offset: &offset, Slug: Slug{text: "hello"}, Author: Author{Name: "bob"}, Small: 4, Path: "b/c"}},
+		{name: "Nil base", v: Listing{Kind: "issues", Offset: &offset}},
+		{name: "Nil page", v: Listing{Base: &Base{Owner: "a"}, Kind: "issues", Offset: &offset}},
+		{name: "Unmatched kind", v: Listing{Base: &Base{Owner: "a", Page: &page}, Kind: "commits", Offset: &offset}},
+		{name: "Escaped owner", v: Listing{Base: &Base{Owner: "a/b", Page: &page}, Kind: "issues", Offset: &offset}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, wantErr := p.Build(&tt.v)
+			got, err := tt.v.BuildPath()
+			if diff := cmp.Diff(errorString(wantErr), errorString(err)); diff != "" {
+				t.Errorf("BuildPath() error mismatch (-want +got):\n%s", diff)
+			}
+			if got != want {
+				t.Errorf("BuildPath() return (%v), which is not what we want.", got)
+			}
+		})
+	}
+}
+
+func TestGitHubIssue(t *testing.T) {
+	var issue GitHubIssue
+	if err := issue.MapPath("/KamikazeZirou/path-mapper/issues/1"); err != nil {
+		t.Fatal(err)
+	}
+	want := GitHubIssue{Owner: "KamikazeZirou", Repository: "path-mapper", Number: 1}
+	if diff := cmp.Diff(want, issue); diff != "" {
+		t.Errorf("MapPath() mismatch (-want +got):\n%s", diff)
+	}
+	if path, err := issue.BuildPath(); err != nil || path != "/KamikazeZirou/path-mapper/issues/1" {
+		t.Errorf("BuildPath() return (%v, %v), which is not what we want.", path, err)
+	}
+}
+
+func TestStatic(t *testing.T) {
+	var s Static
+	if err := s.MapPath("/static/a/b"); err != nil {
+		t.Errorf("MapPath() return (%v), which is not what we want.", err)
+	}
+	p := mapper.MustCompile("/static/{...}")
+	want, wantErr := p.Build(&s)
+	got, err := s.BuildPath()
+	if got != want || errorString(err) != errorString(wantErr) {
+		t.Errorf("BuildPath() return (%v, %v), which is not what we want.", got, err)
+	}
+}
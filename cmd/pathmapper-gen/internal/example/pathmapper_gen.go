@@ -0,0 +1,144 @@
+// Code generated by pathmapper-gen. DO NOT EDIT.
+
+package example
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	mapper "github.com/KamikazeZirou/path-mapper"
+)
+
+var _GitHubIssue_pattern = mapper.MustCompile("/{owner}/{repository}/issues/{number:int}")
+
+// MapPath maps path into v as Pattern.Mapping does with the pattern "/{owner}/{repository}/issues/{number:int}".
+func (v *GitHubIssue) MapPath(path string) error {
+	values, ok := _GitHubIssue_pattern.Values(path)
+	if !ok {
+		return &mapper.MismatchError{Pattern: _GitHubIssue_pattern.String(), Path: path}
+	}
+	v.Owner = values[0]
+	v.Repository = values[1]
+	if n, err := strconv.ParseInt(values[2], 10, 0); err != nil {
+		return &mapper.BindError{Name: "number", Value: values[2], Err: fmt.Errorf("%v is invalid as int", values[2])}
+	} else {
+		v.Number = int(n)
+	}
+	return nil
+}
+
+// BuildPath generates the path that the pattern "/{owner}/{repository}/issues/{number:int}" maps into v, as Pattern.Build does.
+func (v *GitHubIssue) BuildPath() (string, error) {
+	return _GitHubIssue_pattern.BuildFunc(func(i int) (string, error) {
+		switch i {
+		case 0:
+			return v.Owner, nil
+		case 1:
+			return v.Repository, nil
+		case 2:
+			return strconv.FormatInt(int64(v.Number), 10), nil
+		}
+		return "", mapper.ErrNoField
+	})
+}
+
+var _Listing_pattern = mapper.MustCompile("/{owner}/{kind:issues|pulls}/p{page}-{offset}/{slug}/{author.name}/{small}/{missing}/{path...}")
+
+// MapPath maps path into v as Pattern.Mapping does with the pattern "/{owner}/{kind:issues|pulls}/p{page}-{offset}/{slug}/{author.name}/{small}/{missing}/{path...}".
+func (v *Listing) MapPath(path string) error {
+	values, ok := _Listing_pattern.Values(path)
+	if !ok {
+		return &mapper.MismatchError{Pattern: _Listing_pattern.String(), Path: path}
+	}
+	if v.Base == nil {
+		v.Base = new(Base)
+	}
+	if v.Base.Page == nil {
+		v.Base.Page = new(Page)
+	}
+	if v.Offset == nil {
+		v.Offset = new(int64)
+	}
+	v.Base.Owner = values[0]
+	v.Kind = Kind(values[1])
+	if n, err := strconv.ParseUint(values[2], 10, 0); err != nil {
+		return &mapper.BindError{Name: "page", Value: values[2], Err: fmt.Errorf("%v is invalid as uint", values[2])}
+	} else {
+		*v.Base.Page = Page(n)
+	}
+	if n, err := strconv.ParseInt(values[3], 10, 0); err != nil {
+		return &mapper.BindError{Name: "offset", Value: values[3], Err: fmt.Errorf("%v is invalid as int", values[3])}
+	} else {
+		*v.Offset = int64(n)
+	}
+	if parsed, err := v.Slug.Parse(values[4]); err != nil {
+		return &mapper.BindError{Name: "slug", Value: values[4], Err: fmt.Errorf(": %w", err)}
+	} else {
+		v.Slug = parsed.(Slug)
+	}
+	v.Author.Name = values[5]
+	if n, err := strconv.ParseInt(values[6], 10, 0); err != nil {
+		return &mapper.BindError{Name: "small", Value: values[6], Err: fmt.Errorf("%v is invalid as int", values[6])}
+	} else {
+		v.Small = int8(n)
+	}
+	v.Path = values[8]
+	return nil
+}
+
+// BuildPath generates the path that the pattern "/{owner}/{kind:issues|pulls}/p{page}-{offset}/{slug}/{author.name}/{small}/{missing}/{path...}" maps into v, as Pattern.Build does.
+func (v *Listing) BuildPath() (string, error) {
+	return _Listing_pattern.BuildFunc(func(i int) (string, error) {
+		switch i {
+		case 0:
+			if v.Base == nil {
+				return "", mapper.ErrNilField
+			}
+			return v.Base.Owner, nil
+		case 1:
+			return string(v.Kind), nil
+		case 2:
+			if v.Base == nil {
+				return "", mapper.ErrNilField
+			}
+			if v.Base.Page == nil {
+				return "", errors.New("value is nil")
+			}
+			return strconv.FormatUint(uint64(*v.Base.Page), 10), nil
+		case 3:
+			if v.Offset == nil {
+				return "", errors.New("value is nil")
+			}
+			return strconv.FormatInt(int64(*v.Offset), 10), nil
+		case 4:
+			text, err := v.Slug.MarshalText()
+			return string(text), err
+		case 5:
+			return v.Author.Name, nil
+		case 6:
+			return strconv.FormatInt(int64(v.Small), 10), nil
+		case 8:
+			return v.Path, nil
+		}
+		return "", mapper.ErrNoField
+	})
+}
+
+var _Static_pattern = mapper.MustCompile("/static/{...}")
+
+// MapPath maps path into v as Pattern.Mapping does with the pattern "/static/{...}".
+func (v *Static) MapPath(path string) error {
+	_, ok := _Static_pattern.Values(path)
+	if !ok {
+		return &mapper.MismatchError{Pattern: _Static_pattern.String(), Path: path}
+	}
+	return nil
+}
+
+// BuildPath generates the path that the pattern "/static/{...}" maps into v, as Pattern.Build does.
+func (v *Static) BuildPath() (string, error) {
+	return _Static_pattern.BuildFunc(func(int) (string, error) {
+		return "", mapper.ErrNoField
+	})
+}
@@ -0,0 +1,134 @@
+// Command pathmapper-gen generates methods that map paths into structures and build paths from them
+// without reflection, for structure types annotated with a pattern:
+//
+//	//pathmapper:pattern "/{owner}/{repository}/issues/{number}"
+//	type GitHubIssue struct {
+//		Owner      string
+//		Repository string
+//		Number     int
+//	}
+//
+// For each such type T, it generates
+//
+//	func (v *T) MapPath(path string) error
+//	func (v *T) BuildPath() (string, error)
+//
+// which give the same results and errors as Pattern.Mapping and Pattern.Build with a pointer to T.
+// Patterns are compiled without options.
+// BuildPath returns an error rather than only a path because building can fail as Build does:
+// a field may be behind a nil pointer, a value may not satisfy the constraint of its placeholder,
+// and a TextMarshaler may fail. Dropping the error would turn these into a panic or a wrong path.
+//
+// Usage:
+//
+//	pathmapper-gen [-output file] [dir]
+//
+// The package is the one in dir, the current directory by default, and the output defaults to pathmapper_gen.go in dir,
+// so that it can be run by a "//go:generate pathmapper-gen" comment.
+//
+// The struct subcommand writes a structure type with a field for each placeholder of a pattern to the standard output,
+// as a starting point for a new route:
+//
+//	pathmapper-gen struct [-name T] [-package name] pattern
+//
+// Placeholders constrained by int and uint become fields of these types, and the others become strings.
+// Fields are named after their placeholder, with an alias tag where Mapping would not name them after it.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "struct" {
+		if err := runStruct(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "pathmapper-gen: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	output := flag.String("output", "", "output file; default <dir>/pathmapper_gen.go")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: pathmapper-gen [-output file] [dir]\n       pathmapper-gen struct [-name T] [-package name] pattern\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	dir := "."
+	switch flag.NArg() {
+	case 0:
+	case 1:
+		dir = flag.Arg(0)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(dir, *output); err != nil {
+		fmt.Fprintf(os.Stderr, "pathmapper-gen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(dir, output string) error {
+	if output == "" {
+		output = filepath.Join(dir, "pathmapper_gen.go")
+	}
+	pkg, err := load(dir, output)
+	if err != nil {
+		return err
+	}
+	src, err := generate(pkg)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(output, src, 0o644)
+}
+
+// loadedPackage is a parsed and type-checked package.
+type loadedPackage struct {
+	fset  *token.FileSet
+	files []*ast.File
+	types *types.Package
+}
+
+// load parses and type-checks the package in dir, leaving out the output file of an earlier run,
+// which may no longer compile. Type errors are ignored as long as the structures can be resolved,
+// since other files may refer to the methods that are about to be generated.
+func load(dir, output string) (*loadedPackage, error) {
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+	out, err := filepath.Abs(output)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg := &loadedPackage{fset: token.NewFileSet()}
+	for _, name := range bp.GoFiles {
+		path := filepath.Join(bp.Dir, name)
+		if abs, err := filepath.Abs(path); err == nil && abs == out {
+			continue
+		}
+		f, err := parser.ParseFile(pkg.fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		pkg.files = append(pkg.files, f)
+	}
+
+	conf := types.Config{Importer: importer.ForCompiler(pkg.fset, "source", nil), Error: func(error) {}}
+	pkg.types, _ = conf.Check(bp.ImportPath, pkg.fset, pkg.files, nil)
+	return pkg, nil
+}
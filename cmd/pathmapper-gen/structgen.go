@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	mapper "github.com/KamikazeZirou/path-mapper"
+	"github.com/KamikazeZirou/path-mapper/internal/typesx"
+)
+
+// initialisms are the words that Go names write in upper case.
+var initialisms = map[string]bool{"api": true, "http": true, "id": true, "ip": true, "json": true, "uri": true, "url": true, "uuid": true}
+
+// constraintTypes are the field types of the named constraints. Other placeholders, including those
+// constrained by float, become strings, since Mapping cannot assign to floating-point fields.
+var constraintTypes = map[string]string{"int": "int", "uint": "uint"}
+
+func runStruct(args []string) error {
+	flags := flag.NewFlagSet("struct", flag.ExitOnError)
+	name := flags.String("name", "Params", "name of the structure type")
+	pkg := flags.String("package", "", "package clause to write before the type; none by default")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: pathmapper-gen struct [-name T] [-package name] pattern\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	src, err := generateStruct(*pkg, *name, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(src)
+	return err
+}
+
+// generateStruct returns the declaration of a structure type name with a field for each placeholder of pattern,
+// annotated with pattern so that pathmapper-gen generates its mapper. The declaration is a file of package pkg,
+// unless pkg is empty.
+func generateStruct(pkg, name, pattern string) ([]byte, error) {
+	if !token.IsIdentifier(name) {
+		return nil, fmt.Errorf("type name %q is not an identifier", name)
+	}
+	p, err := mapper.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if pkg != "" {
+		fmt.Fprintf(&b, "package %v\n\n", pkg)
+	}
+	fmt.Fprintf(&b, "//pathmapper:pattern %v\ntype %v struct {\n", strconv.Quote(pattern), name)
+	fields := make(map[string]string)
+	constraints := p.Constraints()
+	for i, placeholder := range p.Names() {
+		if placeholder == "" {
+			continue
+		}
+		field := fieldName(placeholder)
+		if other, ok := fields[field]; ok {
+			if other == placeholder {
+				continue
+			}
+			return nil, fmt.Errorf("placeholders %v and %v are both named %v in Go", other, placeholder, field)
+		}
+		if !token.IsIdentifier(field) {
+			return nil, fmt.Errorf("placeholder %v has no Go name", placeholder)
+		}
+		fields[field] = placeholder
+
+		typ, ok := constraintTypes[constraints[i]]
+		if !ok {
+			typ = "string"
+		}
+		fmt.Fprintf(&b, "%v %v", field, typ)
+		if typesx.LcFirst(field) != placeholder {
+			fmt.Fprintf(&b, " `alias:%v`", strconv.Quote(placeholder))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("}\n")
+	return format.Source(b.Bytes())
+}
+
+// fieldName returns the exported Go name of a placeholder, such as RepoID for "repo_id".
+func fieldName(placeholder string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(placeholder, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if initialisms[strings.ToLower(word)] {
+			b.WriteString(strings.ToUpper(word))
+			continue
+		}
+		for i, r := range word {
+			b.WriteRune(unicode.ToUpper(r))
+			b.WriteString(word[i+len(string(r)):])
+			break
+		}
+	}
+	name := b.String()
+	if name != "" && unicode.IsDigit(rune(name[0])) {
+		name = "P" + name
+	}
+	return name
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	mapper "github.com/KamikazeZirou/path-mapper"
+	"github.com/KamikazeZirou/path-mapper/internal/typesx"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGenerateStruct(t *testing.T) {
+	pattern := "/{owner}/{repo_name}/{kind:issues|pulls}/{number:uint}/p{offset:int}/{ratio:float}/{id:uuid}/{author.name}/{path...}"
+	got, err := generateStruct("issues", "RepoIssue", pattern)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `package issues
+
+//pathmapper:pattern "/{owner}/{repo_name}/{kind:issues|pulls}/{number:uint}/p{offset:int}/{ratio:float}/{id:uuid}/{author.name}/{path...}"
+type RepoIssue struct {
+	Owner      string
+	RepoName   string ` + "`" + `alias:"repo_name"` + "`" + `
+	Kind       string
+	Number     uint
+	Offset     int
+	Ratio      string
+	ID         string ` + "`" + `alias:"id"` + "`" + `
+	AuthorName string ` + "`" + `alias:"author.name"` + "`" + `
+	Path       string
+}
+`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Fatalf("generateStruct() mismatch (-want +got):\n%s", diff)
+	}
+
+	// Every placeholder is mapped into the field generated for it.
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "issue.go"), got, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := load(dir, filepath.Join(dir, "pathmapper_gen.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj := pkg.types.Scope().Lookup("RepoIssue")
+	fields := typesx.Fields(obj.Type())
+	for _, name := range mapper.MustCompile(pattern).Names() {
+		if _, ok := fields[name]; !ok {
+			t.Errorf("placeholder %v is not mapped into a field", name)
+		}
+	}
+}
+
+func TestGenerateStruct_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		typ     string
+		pattern string
+	}{
+		{name: "Invalid pattern", typ: "T", pattern: "/{id"},
+		{name: "Invalid type name", typ: "1T", pattern: "/{id}"},
+		{name: "Conflicting field names", typ: "T", pattern: "/{repo_id}/{repoID}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := generateStruct("", tt.typ, tt.pattern); err == nil {
+				t.Errorf("generateStruct() return (%v), which is not what we want.", err)
+			}
+		})
+	}
+}
+
+func TestFieldName(t *testing.T) {
+	tests := []struct {
+		placeholder string
+		want        string
+	}{
+		{placeholder: "owner", want: "Owner"},
+		{placeholder: "repo_id", want: "RepoID"},
+		{placeholder: "pageSize", want: "PageSize"},
+		{placeholder: "x-request-url", want: "XRequestURL"},
+		{placeholder: "2fa", want: "P2fa"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.placeholder, func(t *testing.T) {
+			if got := fieldName(tt.placeholder); got != tt.want {
+				t.Errorf("fieldName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+// Command pathmappercheck checks constant path patterns against the structures they are mapped into.
+// See the pathmappercheck package for the problems it reports.
+//
+// It runs on packages by itself, or as a tool of go vet:
+//
+//	go vet -vettool=$(which pathmappercheck) ./...
+package main
+
+import (
+	"github.com/KamikazeZirou/path-mapper/pathmappercheck"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(pathmappercheck.Analyzer)
+}
@@ -0,0 +1,139 @@
+package path_mapper
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"unicode"
+)
+
+// namedConstraints are the constraints that can be written by name, as in "{id:int}".
+var namedConstraints = map[string]string{
+	"int":   `-?[0-9]+`,
+	"uint":  `[0-9]+`,
+	"float": `-?[0-9]+(?:\.[0-9]+)?`,
+	"uuid":  `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`,
+}
+
+// constraint restricts the values a placeholder captures, as in "{id:int}" or "{kind:issues|pulls}".
+type constraint struct {
+	// text is the constraint as it is written in the pattern.
+	text string
+	// expr is the regular expression the whole value must match.
+	expr string
+	re   *regexp.Regexp
+	// example is a value that satisfies the constraint.
+	example string
+}
+
+// parseConstraint parses the name of a named constraint or a regular expression.
+func parseConstraint(text string) (*constraint, error) {
+	expr, ok := namedConstraints[text]
+	if !ok {
+		expr = text
+	}
+	re, err := regexp.Compile(`^(?:` + expr + `)$`)
+	if err != nil {
+		return nil, fmt.Errorf("invalid constraint %v: %w", text, err)
+	}
+	c := &constraint{text: text, expr: expr, re: re}
+	if parsed, err := syntax.Parse(expr, syntax.Perl); err == nil {
+		var b strings.Builder
+		writeRegexpExample(&b, parsed.Simplify())
+		if re.MatchString(b.String()) {
+			c.example = b.String()
+		}
+	}
+	return c, nil
+}
+
+// parsePlaceholder parses the text between the braces of a placeholder:
+// a name, optionally followed by ":" and a constraint, or a name followed by "..." for a catch-all.
+func parsePlaceholder(text string) (segment, error) {
+	name, constraintText, hasConstraint := strings.Cut(text, ":")
+	if strings.HasSuffix(name, "...") {
+		if hasConstraint {
+			return segment{}, fmt.Errorf("catch-all {%v} cannot have a constraint", text)
+		}
+		return segment{kind: catchAllSegment, value: strings.TrimSuffix(name, "...")}, nil
+	}
+	if name == "" {
+		return segment{}, errors.New("placeholder has no name")
+	}
+
+	s := segment{kind: placeholderSegment, value: name}
+	if hasConstraint {
+		c, err := parseConstraint(constraintText)
+		if err != nil {
+			return segment{}, err
+		}
+		s.constraint = c
+	}
+	return s, nil
+}
+
+// allows reports whether a placeholder accepts value.
+func (s *segment) allows(value string) bool {
+	return s.constraint == nil || s.constraint.re.MatchString(value)
+}
+
+// constraintKey returns the constraint of a placeholder as written, or "" if it has none.
+func (s *segment) constraintKey() string {
+	if s.constraint == nil {
+		return ""
+	}
+	return s.constraint.text
+}
+
+// writeRegexpExample writes a short string that matches re.
+func writeRegexpExample(b *strings.Builder, re *syntax.Regexp) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			b.WriteRune(r)
+		}
+	case syntax.OpCharClass:
+		b.WriteRune(exampleRune(re.Rune))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		b.WriteByte('a')
+	case syntax.OpCapture, syntax.OpPlus:
+		writeRegexpExample(b, re.Sub[0])
+	case syntax.OpRepeat:
+		for i := 0; i < re.Min; i++ {
+			writeRegexpExample(b, re.Sub[0])
+		}
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			writeRegexpExample(b, sub)
+		}
+	case syntax.OpAlternate:
+		writeRegexpExample(b, re.Sub[0])
+	}
+}
+
+// exampleRune returns a readable rune of a character class, given as pairs of ranges.
+func exampleRune(ranges []rune) rune {
+	for _, r := range "a0A-_" {
+		for i := 0; i+1 < len(ranges); i += 2 {
+			if ranges[i] <= r && r <= ranges[i+1] {
+				return r
+			}
+		}
+	}
+	for i := 0; i+1 < len(ranges); i += 2 {
+		for r := ranges[i]; r <= ranges[i+1]; r++ {
+			if unicode.IsPrint(r) {
+				return r
+			}
+			if r-ranges[i] > 0x100 {
+				break
+			}
+		}
+	}
+	if len(ranges) == 0 {
+		return 'a'
+	}
+	return ranges[0]
+}
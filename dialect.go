@@ -0,0 +1,351 @@
+package path_mapper
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Dialect is the pattern syntax of a router, which WithDialect translates into the syntax of this package.
+// Dialects assume "/" as the separator.
+type Dialect int
+
+const (
+	// Native is the syntax of this package.
+	Native Dialect = iota
+	// Gin is the syntax of gin and httprouter: ":name" captures the rest of a segment,
+	// as in "/users/:id" or "/files/v:version", and "*name", which must be the last segment, captures the rest of the path.
+	Gin
+	// Echo is the syntax of echo: ":name" captures the rest of a segment,
+	// and "*", which must be the last segment, captures the rest of the path without a name.
+	Echo
+	// Express is the syntax of Express 4: ":name" captures text up to the next character that is not a letter, digit or "_",
+	// as in "/flights/:from-:to", and may be followed by a regular expression in parentheses, as in "/users/:id(\\d+)".
+	// A last segment ":name*", ":name+", "*name" or "*" captures the rest of the path.
+	// Optional parameters and regular expression syntax outside parameters are not supported.
+	Express
+	// Flask is the syntax of Flask and Werkzeug: "<name>" or "<converter:name>" captures text within a segment, as in "/users/<int:id>".
+	// The converters string, int, float, uuid and any become constraints, and path, which must be the last segment,
+	// captures the rest of the path. Of the converter arguments, only signed=True and the values of any are supported.
+	Flask
+	// Chi is the syntax of chi: "{name}" or "{name:regexp}" captures text within a segment,
+	// and "*", which must be the last segment, captures the rest of the path without a name.
+	// A regular expression is never taken for the name of a constraint of this package.
+	Chi
+	// OpenAPI is the syntax of path templates of OpenAPI: "{name}" captures text within a segment.
+	OpenAPI
+)
+
+// String returns the name of the dialect.
+func (d Dialect) String() string {
+	switch d {
+	case Native:
+		return "native"
+	case Gin:
+		return "gin"
+	case Echo:
+		return "echo"
+	case Express:
+		return "Express"
+	case Flask:
+		return "Flask"
+	case Chi:
+		return "chi"
+	case OpenAPI:
+		return "OpenAPI"
+	}
+	return fmt.Sprintf("Dialect(%d)", int(d))
+}
+
+// WithDialect makes patterns written in the syntax of another router, such as "/users/:id/*rest" of gin, compile as patterns of this package.
+// Patterns keep their source text: String returns it, and Join expects a pattern in the same dialect.
+func WithDialect(d Dialect) Option {
+	return func(o *options) {
+		o.dialect = d
+	}
+}
+
+// TranslatePattern translates a pattern written in the syntax of d into the syntax of this package,
+// such as "/users/:id/*rest" of gin into "/users/{id}/{rest...}".
+func TranslatePattern(pattern string, d Dialect) (string, error) {
+	var t dialectTranslator
+	switch d {
+	case Native:
+		return pattern, nil
+	case Gin, Echo:
+		t = translateGin
+	case Express:
+		t = translateExpress
+	case Flask:
+		t = translateFlask
+	case Chi, OpenAPI:
+		t = translateChi
+	default:
+		return "", fmt.Errorf("unknown dialect %v", d)
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(pattern); {
+		n, err := t(&b, pattern, i, d)
+		if err != nil {
+			return "", fmt.Errorf("%v pattern(%v): %w", d, pattern, err)
+		}
+		if n == 0 {
+			if pattern[i] == '{' || pattern[i] == '}' {
+				return "", fmt.Errorf("%v pattern(%v): %c is not supported", d, pattern, pattern[i])
+			}
+			b.WriteByte(pattern[i])
+			n = 1
+		}
+		i += n
+	}
+	return b.String(), nil
+}
+
+// dialectTranslator translates the syntax that starts at pattern[i] and returns the number of bytes it consumed,
+// or 0 if pattern[i] is literal text.
+type dialectTranslator func(b *strings.Builder, pattern string, i int, d Dialect) (int, error)
+
+func translateGin(b *strings.Builder, pattern string, i int, d Dialect) (int, error) {
+	switch pattern[i] {
+	case ':':
+		name := pattern[i+1:]
+		if j := strings.IndexByte(name, '/'); j >= 0 {
+			name = name[:j]
+		}
+		if !validParamName(name) {
+			return 0, fmt.Errorf("invalid parameter name %q", name)
+		}
+		fmt.Fprintf(b, "{%v}", name)
+		return 1 + len(name), nil
+	case '*':
+		name := pattern[i+1:]
+		if !startsSegment(pattern, i) || strings.IndexByte(name, '/') >= 0 {
+			return 0, fmt.Errorf("%v must be the last segment", pattern[i:])
+		}
+		if d == Echo && name != "" {
+			return 0, fmt.Errorf("wildcard %v cannot have a name", pattern[i:])
+		}
+		if d == Gin && !validParamName(name) {
+			return 0, fmt.Errorf("invalid catch-all name %q", name)
+		}
+		fmt.Fprintf(b, "{%v...}", name)
+		return len(pattern) - i, nil
+	}
+	return 0, nil
+}
+
+func translateExpress(b *strings.Builder, pattern string, i int, _ Dialect) (int, error) {
+	switch pattern[i] {
+	case '\\':
+		if i+1 == len(pattern) {
+			return 0, fmt.Errorf("trailing \\")
+		}
+		b.WriteByte(pattern[i+1])
+		return 2, nil
+	case ':':
+		j := i + 1
+		for j < len(pattern) && isWordChar(pattern[j]) {
+			j++
+		}
+		name := pattern[i+1 : j]
+		if name == "" {
+			return 0, fmt.Errorf(": at %d has no parameter name", i)
+		}
+
+		var expr string
+		if j < len(pattern) && pattern[j] == '(' {
+			end := closingParen(pattern, j)
+			if end < 0 {
+				return 0, fmt.Errorf("unclosed ( after :%v", name)
+			}
+			expr, j = pattern[j+1:end], end+1
+		}
+
+		if j < len(pattern) {
+			switch pattern[j] {
+			case '?':
+				return 0, fmt.Errorf("optional parameter :%v is not supported", name)
+			case '*', '+':
+				if !startsSegment(pattern, i) || j+1 != len(pattern) || expr != "" {
+					return 0, fmt.Errorf("repeated parameter :%v%c must be the last segment, without a regular expression", name, pattern[j])
+				}
+				fmt.Fprintf(b, "{%v...}", name)
+				return len(pattern) - i, nil
+			}
+		}
+		if expr != "" {
+			fmt.Fprintf(b, "{%v:%v}", name, expr)
+		} else {
+			fmt.Fprintf(b, "{%v}", name)
+		}
+		return j - i, nil
+	case '*':
+		name := pattern[i+1:]
+		if !startsSegment(pattern, i) || name != "" && !validParamName(name) {
+			return 0, fmt.Errorf("wildcard %v must be the last segment", pattern[i:])
+		}
+		fmt.Fprintf(b, "{%v...}", name)
+		return len(pattern) - i, nil
+	case '(', ')', '?', '+':
+		return 0, fmt.Errorf("%c outside a parameter is not supported", pattern[i])
+	}
+	return 0, nil
+}
+
+func translateChi(b *strings.Builder, pattern string, i int, d Dialect) (int, error) {
+	switch pattern[i] {
+	case '{':
+		end := closingBrace(pattern, i)
+		if end < 0 {
+			return 0, fmt.Errorf("unclosed { at %d", i)
+		}
+		name, expr, hasExpr := strings.Cut(pattern[i+1:end], ":")
+		if name == "" || strings.HasSuffix(name, "...") || d == OpenAPI && hasExpr {
+			return 0, fmt.Errorf("invalid parameter %v", pattern[i:end+1])
+		}
+		if !hasExpr {
+			fmt.Fprintf(b, "{%v}", name)
+			return end + 1 - i, nil
+		}
+		if _, ok := namedConstraints[expr]; ok {
+			expr = "(?:" + expr + ")"
+		}
+		fmt.Fprintf(b, "{%v:%v}", name, expr)
+		return end + 1 - i, nil
+	case '*':
+		if d == OpenAPI {
+			return 0, nil
+		}
+		if !startsSegment(pattern, i) || i+1 != len(pattern) {
+			return 0, fmt.Errorf("wildcard * must be the last segment")
+		}
+		b.WriteString("{...}")
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// flaskConverters are the regular expressions of the converters of Werkzeug, except path and any.
+var flaskConverters = map[string]string{
+	"default": "",
+	"string":  "",
+	"int":     "uint",
+	"float":   `[0-9]+\.[0-9]+`,
+	"uuid":    "uuid",
+}
+
+// flaskSigned are the constraints of the converters that take signed=True.
+var flaskSigned = map[string]string{
+	"int":   "int",
+	"float": `-?[0-9]+\.[0-9]+`,
+}
+
+func translateFlask(b *strings.Builder, pattern string, i int, _ Dialect) (int, error) {
+	if pattern[i] != '<' {
+		if pattern[i] == '>' {
+			return 0, fmt.Errorf("unexpected > at %d", i)
+		}
+		return 0, nil
+	}
+	end := strings.IndexByte(pattern[i:], '>')
+	if end < 0 {
+		return 0, fmt.Errorf("unclosed < at %d", i)
+	}
+	rule := pattern[i+1 : i+end]
+
+	converter, name := "default", rule
+	if j := strings.LastIndexByte(rule, ':'); j >= 0 {
+		converter, name = rule[:j], rule[j+1:]
+	}
+	if !validParamName(name) {
+		return 0, fmt.Errorf("invalid variable name %q", name)
+	}
+	var args string
+	if j := strings.IndexByte(converter, '('); j >= 0 {
+		if !strings.HasSuffix(converter, ")") {
+			return 0, fmt.Errorf("invalid converter %v", converter)
+		}
+		converter, args = converter[:j], strings.TrimSpace(converter[j+1:len(converter)-1])
+	}
+
+	var expr string
+	switch converter {
+	case "path":
+		if args != "" || !startsSegment(pattern, i) || i+end+1 != len(pattern) {
+			return 0, fmt.Errorf("<%v> must be the last segment, without arguments", rule)
+		}
+		fmt.Fprintf(b, "{%v...}", name)
+		return end + 1, nil
+	case "any":
+		var values []string
+		for _, v := range strings.Split(args, ",") {
+			v = strings.Trim(strings.TrimSpace(v), `"'`)
+			if v == "" {
+				return 0, fmt.Errorf("<%v> has an empty value", rule)
+			}
+			values = append(values, regexp.QuoteMeta(v))
+		}
+		expr = strings.Join(values, "|")
+	default:
+		var ok bool
+		if expr, ok = flaskConverters[converter]; !ok {
+			return 0, fmt.Errorf("unknown converter %v", converter)
+		}
+		if args != "" {
+			signed, ok := flaskSigned[converter]
+			if !ok || strings.ReplaceAll(args, " ", "") != "signed=True" {
+				return 0, fmt.Errorf("arguments of <%v> are not supported", rule)
+			}
+			expr = signed
+		}
+	}
+
+	if expr != "" {
+		fmt.Fprintf(b, "{%v:%v}", name, expr)
+	} else {
+		fmt.Fprintf(b, "{%v}", name)
+	}
+	return end + 1, nil
+}
+
+// startsSegment reports whether pattern[i] is the first character of a segment.
+func startsSegment(pattern string, i int) bool {
+	return i == 0 || pattern[i-1] == '/'
+}
+
+// closingParen returns the index of the parenthesis that closes the one at s[open], or -1.
+// Escaped parentheses are skipped.
+func closingParen(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+// validParamName reports whether s is a non-empty name of letters, digits and "_".
+func validParamName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isWordChar(s[i]) {
+			return false
+		}
+	}
+	return true
+}
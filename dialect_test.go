@@ -0,0 +1,105 @@
+package path_mapper
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTranslatePattern(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		pattern string
+		want    string
+		success bool
+	}{
+		{name: "Native", dialect: Native, pattern: "/users/{id}", want: "/users/{id}", success: true},
+		{name: "gin parameters", dialect: Gin, pattern: "/users/:id/*rest", want: "/users/{id}/{rest...}", success: true},
+		{name: "gin parameter after text", dialect: Gin, pattern: "/files/v:version", want: "/files/v{version}", success: true},
+		{name: "gin catch-all needs a name", dialect: Gin, pattern: "/files/*", success: false},
+		{name: "gin catch-all must be last", dialect: Gin, pattern: "/files/*path/raw", success: false},
+		{name: "gin invalid name", dialect: Gin, pattern: "/users/:id.json", success: false},
+		{name: "echo wildcard", dialect: Echo, pattern: "/static/*", want: "/static/{...}", success: true},
+		{name: "echo wildcard cannot have a name", dialect: Echo, pattern: "/static/*path", success: false},
+		{name: "Express parameters within a segment", dialect: Express, pattern: "/flights/:from-:to", want: "/flights/{from}-{to}", success: true},
+		{name: "Express regular expression", dialect: Express, pattern: `/users/:id(\d{1,8})`, want: `/users/{id:\d{1,8}}`, success: true},
+		{name: "Express repeated parameter", dialect: Express, pattern: "/files/:path*", want: "/files/{path...}", success: true},
+		{name: "Express wildcard", dialect: Express, pattern: "/assets/*", want: "/assets/{...}", success: true},
+		{name: "Express escape", dialect: Express, pattern: `/files/\:name/:name`, want: "/files/:name/{name}", success: true},
+		{name: "Express optional parameter", dialect: Express, pattern: "/users/:id?", success: false},
+		{name: "Express unnamed group", dialect: Express, pattern: "/users/(\\d+)", success: false},
+		{name: "Express braces", dialect: Express, pattern: "/users{/:id}", success: false},
+		{name: "Flask converters", dialect: Flask, pattern: "/users/<int:id>/<name>", want: "/users/{id:uint}/{name}", success: true},
+		{name: "Flask signed", dialect: Flask, pattern: "/offsets/<int(signed=True):n>", want: "/offsets/{n:int}", success: true},
+		{name: "Flask uuid and float", dialect: Flask, pattern: "/<uuid:id>/<float:v>", want: `/{id:uuid}/{v:[0-9]+\.[0-9]+}`, success: true},
+		{name: "Flask any", dialect: Flask, pattern: "/<any(issues, 'pulls'):kind>/<id>.json", want: "/{kind:issues|pulls}/{id}.json", success: true},
+		{name: "Flask path", dialect: Flask, pattern: "/files/<path:path>", want: "/files/{path...}", success: true},
+		{name: "Flask path must be last", dialect: Flask, pattern: "/files/<path:path>/raw", success: false},
+		{name: "Flask unknown converter", dialect: Flask, pattern: "/<date:day>", success: false},
+		{name: "Flask unsupported argument", dialect: Flask, pattern: "/<int(min=1):id>", success: false},
+		{name: "chi", dialect: Chi, pattern: "/users/{id:[0-9]{1,8}}/{day}.log/*", want: "/users/{id:[0-9]{1,8}}/{day}.log/{...}", success: true},
+		{name: "chi regular expression named like a constraint", dialect: Chi, pattern: "/{id:int}", want: "/{id:(?:int)}", success: true},
+		{name: "chi wildcard must be last", dialect: Chi, pattern: "/*/raw", success: false},
+		{name: "OpenAPI", dialect: OpenAPI, pattern: "/repos/{owner}/{repo}", want: "/repos/{owner}/{repo}", success: true},
+		{name: "OpenAPI constraint", dialect: OpenAPI, pattern: "/repos/{id:int}", success: false},
+		{name: "Flask unclosed", dialect: Flask, pattern: "/<int:id", success: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := TranslatePattern(tt.pattern, tt.dialect)
+			if (err == nil) != tt.success {
+				t.Fatalf("TranslatePattern() return (%v), which is not what we want.", err)
+			}
+			if got != tt.want {
+				t.Errorf("TranslatePattern() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type FlaskUser struct {
+	ID   int `alias:"user_id"`
+	Rest string
+}
+
+func TestWithDialect(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		pattern string
+		path    string
+		want    FlaskUser
+		success bool
+	}{
+		{name: "gin", dialect: Gin, pattern: "/users/:user_id/*rest", path: "/users/1/a/b", want: FlaskUser{ID: 1, Rest: "a/b"}, success: true},
+		{name: "Express", dialect: Express, pattern: `/users/:user_id(\d+)/:rest`, path: "/users/1/posts", want: FlaskUser{ID: 1, Rest: "posts"}, success: true},
+		{name: "Express constraint", dialect: Express, pattern: `/users/:user_id(\d+)`, path: "/users/me", success: false},
+		{name: "Flask", dialect: Flask, pattern: "/users/<int:user_id>/<path:rest>", path: "/users/1/a/b", want: FlaskUser{ID: 1, Rest: "a/b"}, success: true},
+		{name: "Flask constraint", dialect: Flask, pattern: "/users/<int:user_id>", path: "/users/-1", success: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got FlaskUser
+			err := Mapping(tt.pattern, tt.path, &got, WithDialect(tt.dialect))
+			if (err == nil) != tt.success {
+				t.Fatalf("Mapping() return (%v), which is not what we want.", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Mapping() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	s := NewPatternSet(WithDialect(Gin))
+	for _, pattern := range []string{"/users/:user_id", "/users/me"} {
+		if err := s.Add(pattern, pattern); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if m, ok := s.Match("/users/me"); !ok || m.Value != "/users/me" || m.Pattern.String() != "/users/me" {
+		t.Errorf("Match() = %v, want the literal pattern", m)
+	}
+}
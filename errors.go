@@ -0,0 +1,44 @@
+package path_mapper
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MismatchError is returned when a path does not match a pattern.
+type MismatchError struct {
+	Pattern string
+	Path    string
+}
+
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf("pattern(%v) does not match path(%v)", e.Pattern, e.Path)
+}
+
+// BindError is returned when a captured value cannot be assigned to the field of the destination.
+type BindError struct {
+	// Name is the placeholder that captured Value.
+	Name  string
+	Value string
+	Err   error
+}
+
+func (e *BindError) Error() string {
+	return fmt.Sprintf("failed mapping %v into %v : %v", e.Value, e.Name, e.Err)
+}
+
+func (e *BindError) Unwrap() error {
+	return e.Err
+}
+
+// UnsupportedError is returned when a pattern uses features that cannot be written in a dialect.
+type UnsupportedError struct {
+	Pattern string
+	Dialect Dialect
+	// Features describes each part of the pattern that cannot be written.
+	Features []string
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("pattern(%v) cannot be written for %v: %v", e.Pattern, e.Dialect, strings.Join(e.Features, ", "))
+}
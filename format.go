@@ -0,0 +1,233 @@
+package path_mapper
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Format writes the pattern in the syntax of d, such as "/repos/:owner/:repo" for gin,
+// so that configurations of other routers and gateways can be generated from a single pattern.
+// It fails with an *UnsupportedError if the pattern uses features that d cannot represent:
+//
+//   - Chi and Echo have no named catch-all, so a catch-all becomes "*" and loses its name.
+//   - Gin and Echo have no constraints, and a parameter must be the end of its segment.
+//   - In Express, a parameter must not be followed by a letter, digit or "_".
+//   - Flask has no regular expressions; a constraint that lists literal values becomes any, and int, uint and uuid become converters.
+//   - OpenAPI path templates have no constraints and no catch-alls; constraints belong in the schemas of the parameters.
+//
+// Every dialect requires "/" as the separator.
+func (p *Pattern) Format(d Dialect) (string, error) {
+	return p.format(d, false)
+}
+
+// format writes the pattern in the syntax of d. If noConstraints is true, constraints are left out instead of being reported.
+func (p *Pattern) format(d Dialect, noConstraints bool) (string, error) {
+	f := &formatter{dialect: d, noConstraints: noConstraints}
+	switch d {
+	case Native:
+	case Chi, Gin, Echo, Express, Flask, OpenAPI:
+		for _, s := range p.segments {
+			if s.sep != 0 && s.sep != '/' {
+				f.unsupported("separator %q", s.sep)
+			}
+		}
+	default:
+		return "", fmt.Errorf("unknown dialect %v", d)
+	}
+
+	for _, s := range p.segments {
+		if s.sep != 0 {
+			f.b.WriteByte(s.sep)
+		}
+		switch s.kind {
+		case literalSegment:
+			f.literal(s.value)
+		case placeholderSegment:
+			f.placeholder(s)
+		case catchAllSegment:
+			f.catchAll(s)
+		case mixedSegment:
+			f.mixed(s)
+		}
+	}
+
+	if f.features != nil {
+		return "", &UnsupportedError{Pattern: p.raw, Dialect: d, Features: f.features}
+	}
+	return f.b.String(), nil
+}
+
+type formatter struct {
+	dialect       Dialect
+	noConstraints bool
+	b             strings.Builder
+	features      []string
+}
+
+func (f *formatter) unsupported(format string, args ...interface{}) {
+	f.features = append(f.features, fmt.Sprintf(format, args...))
+}
+
+// literal writes literal text, escaping or reporting the characters that are special in the dialect.
+func (f *formatter) literal(text string) {
+	var special string
+	switch f.dialect {
+	case Chi:
+		special = "*"
+	case Gin, Echo:
+		special = ":*"
+	case Express:
+		special = "()?+*"
+		text = strings.ReplaceAll(strings.ReplaceAll(text, `\`, `\\`), ":", `\:`)
+	case Flask:
+		special = "<>"
+	}
+	if i := strings.IndexAny(text, special); i >= 0 {
+		f.unsupported("%q in literal %v", text[i], text)
+	}
+	f.b.WriteString(text)
+}
+
+func (f *formatter) placeholder(s segment) {
+	name := s.value
+	if f.dialect != Native && f.dialect != Chi && f.dialect != OpenAPI && !validParamName(name) {
+		f.unsupported("placeholder name %v", name)
+	}
+	c := s.constraint
+	if c != nil && f.noConstraints {
+		c = nil
+	}
+
+	switch f.dialect {
+	case Native:
+		if c == nil {
+			s.constraint = nil
+		}
+		f.b.WriteString(s.String())
+	case Chi:
+		if c == nil {
+			fmt.Fprintf(&f.b, "{%v}", name)
+			return
+		}
+		expr := c.expr
+		if strings.Contains(expr, "|") {
+			expr = "(?:" + expr + ")"
+		}
+		fmt.Fprintf(&f.b, "{%v:%v}", name, expr)
+	case Gin, Echo:
+		if c != nil {
+			f.unsupported("constraint of %v", s)
+		}
+		fmt.Fprintf(&f.b, ":%v", name)
+	case Express:
+		if c == nil {
+			fmt.Fprintf(&f.b, ":%v", name)
+			return
+		}
+		fmt.Fprintf(&f.b, ":%v(%v)", name, c.expr)
+	case Flask:
+		if c == nil {
+			fmt.Fprintf(&f.b, "<%v>", name)
+			return
+		}
+		switch c.text {
+		case "uint":
+			fmt.Fprintf(&f.b, "<int:%v>", name)
+		case "int":
+			fmt.Fprintf(&f.b, "<int(signed=True):%v>", name)
+		case "uuid":
+			fmt.Fprintf(&f.b, "<uuid:%v>", name)
+		default:
+			values, ok := literalAlternatives(c.expr)
+			if !ok || strings.ContainsAny(strings.Join(values, ""), "',") {
+				f.unsupported("constraint of %v", s)
+				return
+			}
+			for i, v := range values {
+				values[i] = "'" + v + "'"
+			}
+			fmt.Fprintf(&f.b, "<any(%v):%v>", strings.Join(values, ", "), name)
+		}
+	case OpenAPI:
+		if c != nil {
+			f.unsupported("constraint of %v", s)
+		}
+		fmt.Fprintf(&f.b, "{%v}", name)
+	}
+}
+
+func (f *formatter) catchAll(s segment) {
+	name := s.value
+	switch f.dialect {
+	case Native:
+		f.b.WriteString(s.String())
+	case Chi:
+		f.b.WriteByte('*')
+	case Gin:
+		if name == "" {
+			f.unsupported("unnamed catch-all")
+		}
+		f.b.WriteString("*" + name)
+	case Echo:
+		f.b.WriteByte('*')
+	case Express:
+		if name == "" {
+			f.b.WriteByte('*')
+			return
+		}
+		if !validParamName(name) {
+			f.unsupported("catch-all name %v", name)
+		}
+		f.b.WriteString(":" + name + "*")
+	case Flask:
+		if !validParamName(name) {
+			f.unsupported("catch-all name %q", name)
+		}
+		f.b.WriteString("<path:" + name + ">")
+	case OpenAPI:
+		f.unsupported("catch-all %v", s)
+	}
+}
+
+func (f *formatter) mixed(s segment) {
+	if (f.dialect == Gin || f.dialect == Echo) && (len(s.parts) != 2 || s.parts[1].kind != placeholderSegment) {
+		f.unsupported("mixed segment %v", s)
+	}
+
+	for i, part := range s.parts {
+		if part.kind == literalSegment {
+			f.literal(part.value)
+			continue
+		}
+		if f.dialect == Express && i+1 < len(s.parts) && isWordChar(s.parts[i+1].value[0]) {
+			f.unsupported("%v followed by %v in mixed segment %v", part, s.parts[i+1].value, s)
+		}
+		f.placeholder(part)
+	}
+}
+
+// literalAlternatives returns the values of a regular expression that only lists literal values, such as "issues|pulls".
+func literalAlternatives(expr string) ([]string, bool) {
+	var values []string
+	for _, alt := range strings.Split(expr, "|") {
+		value := unescapeRegexp(alt)
+		if alt == "" || regexp.QuoteMeta(value) != alt {
+			return nil, false
+		}
+		values = append(values, value)
+	}
+	return values, true
+}
+
+// unescapeRegexp removes the backslashes that escape punctuation, as written by regexp.QuoteMeta.
+func unescapeRegexp(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,73 @@
+package path_mapper
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPattern_Format(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		dialect Dialect
+		want    string
+		// unsupported lists the features reported by an *UnsupportedError.
+		unsupported []string
+	}{
+		{name: "Native", pattern: "/repos/{owner}/{id:int}/{path...}", dialect: Native, want: "/repos/{owner}/{id:int}/{path...}"},
+		{name: "chi", pattern: "/repos/{owner}/{kind:issues|pulls}/{id:int}", dialect: Chi, want: "/repos/{owner}/{kind:(?:issues|pulls)}/{id:-?[0-9]+}"},
+		{name: "chi catch-all", pattern: "/files/{path...}", dialect: Chi, want: "/files/*"},
+		{name: "chi mixed segment", pattern: "/logs/{day}.log", dialect: Chi, want: "/logs/{day}.log"},
+		{name: "gin", pattern: "/repos/{owner}/v{version}/{path...}", dialect: Gin, want: "/repos/:owner/v:version/*path"},
+		{name: "gin constraint", pattern: "/issues/{id:int}", dialect: Gin, unsupported: []string{"constraint of {id:int}"}},
+		{name: "gin mixed segment", pattern: "/logs/{day}.log/{...}", dialect: Gin, unsupported: []string{"mixed segment {day}.log", "unnamed catch-all"}},
+		{name: "gin literal", pattern: "/a:b", dialect: Gin, unsupported: []string{`':' in literal a:b`}},
+		{name: "echo", pattern: "/static/{path...}", dialect: Echo, want: "/static/*"},
+		{name: "Express", pattern: `/flights/{from}-{to}/{id:\d+}/{path...}`, dialect: Express, want: `/flights/:from-:to/:id(\d+)/:path*`},
+		{name: "Express escape", pattern: "/a:b/{...}", dialect: Express, want: `/a\:b/*`},
+		{name: "Express mixed segment", pattern: "/{name}v{version}", dialect: Express, unsupported: []string{"{name} followed by v in mixed segment {name}v{version}"}},
+		{name: "Flask", pattern: "/{id:uint}/{n:int}/{u:uuid}/{kind:issues|pulls}/{name}.json/{path...}", dialect: Flask,
+			want: "/<int:id>/<int(signed=True):n>/<uuid:u>/<any('issues', 'pulls'):kind>/<name>.json/<path:path>"},
+		{name: "Flask regular expression", pattern: `/{id:\d+}`, dialect: Flask, unsupported: []string{`constraint of {id:\d+}`}},
+		{name: "OpenAPI", pattern: "/repos/{owner}/{repo}/reports/{day}.json", dialect: OpenAPI, want: "/repos/{owner}/{repo}/reports/{day}.json"},
+		{name: "OpenAPI constraint and catch-all", pattern: "/repos/{id:int}/{path...}", dialect: OpenAPI,
+			unsupported: []string{"constraint of {id:int}", "catch-all {path...}"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MustCompile(tt.pattern).Format(tt.dialect)
+			if tt.unsupported != nil {
+				var unsupported *UnsupportedError
+				if !errors.As(err, &unsupported) {
+					t.Fatalf("Format() return (%v), which is not what we want.", err)
+				}
+				if diff := cmp.Diff(tt.unsupported, unsupported.Features); diff != "" {
+					t.Errorf("Format() features mismatch (-want +got):\n%s", diff)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Format() return (%v), which is not what we want.", err)
+			}
+			if got != tt.want {
+				t.Errorf("Format() = %v, want %v", got, tt.want)
+			}
+
+			// The formatted pattern translates back into a pattern that matches the same paths.
+			translated, err := TranslatePattern(got, tt.dialect)
+			if err != nil {
+				t.Fatalf("TranslatePattern(%v) return (%v), which is not what we want.", got, err)
+			}
+			if tt.dialect != Chi && tt.dialect != Echo && translated != tt.pattern {
+				t.Errorf("TranslatePattern(%v) = %v, want %v", got, translated, tt.pattern)
+			}
+		})
+	}
+
+	if _, err := MustCompile("user:{id}", WithSeparators(":")).Format(OpenAPI); err == nil {
+		t.Errorf("Format() with separator : return nil, want an error")
+	}
+}
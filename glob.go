@@ -0,0 +1,43 @@
+package path_mapper
+
+import "strings"
+
+// Glob returns a glob for filepath.Glob or path.Match that matches every path
+// the pattern can map into src, which is a structure or a pointer to one.
+// Placeholders are replaced as in Build, or with "*" if the field is missing, nil or the zero value of its type,
+// except that values are written as they are instead of percent-encoded, and a value that contains a separator is an error
+// unless it is the value of a catch-all.
+// A catch-all without a value also becomes "*" and so matches a single segment,
+// since the glob syntax has no wildcard for any number of segments.
+// The metacharacters of the glob syntax in literals and values are escaped with a backslash.
+func (p *Pattern) Glob(src interface{}) (string, error) {
+	return p.build(src, p.rawValue, escapeGlob, func(b *strings.Builder, s segment) bool {
+		b.WriteString("*")
+		return true
+	})
+}
+
+// Prefix returns the longest prefix shared by every path the pattern can map into src,
+// such as the prefix to list in an object store.
+// The path is built as in Build up to the first placeholder whose field is missing, nil or the zero value of its type,
+// except that values are written as they are, as the keys of an object store are, instead of percent-encoded,
+// and a value that contains a separator is an error unless it is the value of a catch-all.
+func (p *Pattern) Prefix(src interface{}) (string, error) {
+	return p.build(src, p.rawValue, nil, func(b *strings.Builder, s segment) bool {
+		return false
+	})
+}
+
+func escapeGlob(s string) string {
+	if !strings.ContainsAny(s, `*?[\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(`*?[\`, s[i]) >= 0 {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
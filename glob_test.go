@@ -0,0 +1,202 @@
+package path_mapper
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type ObjectKey struct {
+	Bucket string
+	Year   int
+	Month  int
+	File   string
+}
+
+func TestPattern_Glob(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		opts    []Option
+		src     interface{}
+		want    string
+	}{
+		{
+			name:    "Zero fields are wildcards",
+			pattern: "{bucket}/{year}/{month}/{file}",
+			src:     ObjectKey{Bucket: "logs", Year: 2024},
+			want:    "logs/2024/*/*",
+		},
+		{
+			name:    "Fields after a wildcard are kept",
+			pattern: "{bucket}/{year}/{month}/{file}",
+			src:     &ObjectKey{Bucket: "logs", File: "app.log"},
+			want:    "logs/*/*/app.log",
+		},
+		{
+			name:    "Mixed segment",
+			pattern: "{bucket}/{file}.log",
+			src:     ObjectKey{Bucket: "logs"},
+			want:    "logs/*.log",
+		},
+		{
+			name:    "Catch-all",
+			pattern: "{bucket}/{file...}",
+			src:     ObjectKey{Bucket: "logs"},
+			want:    "logs/*",
+		},
+		{
+			name:    "Values are not percent-encoded",
+			pattern: "{bucket}/{year}/{month}/{file}",
+			src:     ObjectKey{Bucket: "my logs", Year: 2024},
+			want:    "my logs/2024/*/*",
+		},
+		{
+			name:    "Metacharacters of values are escaped",
+			pattern: "{bucket}/{file}",
+			src:     ObjectKey{Bucket: "a*b?"},
+			want:    `a\*b\?/*`,
+		},
+		{
+			name:    "Catch-all value keeps its separators",
+			pattern: "{bucket}/{file...}",
+			src:     ObjectKey{Bucket: "logs", File: "2024/app log"},
+			want:    "logs/2024/app log",
+		},
+		{
+			name:    "Metacharacters are escaped",
+			pattern: "[{bucket}]/{file}",
+			opts:    []Option{WithoutDecoding()},
+			src:     ObjectKey{Bucket: "a*b"},
+			want:    `\[a\*b]/*`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MustCompile(tt.pattern, tt.opts...).Glob(tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Glob() = %v, want %v", got, tt.want)
+			}
+			if _, err := path.Match(got, ""); err != nil {
+				t.Errorf("Glob() return an invalid glob %v: %v", got, err)
+			}
+		})
+	}
+}
+
+func TestPattern_Glob_Matches(t *testing.T) {
+	p := MustCompile("{bucket}/{year}/{month}/{file}")
+	glob, err := p.Glob(ObjectKey{Bucket: "logs", Year: 2024})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]bool{
+		"logs/2024/01/app.log": true,
+		"logs/2023/01/app.log": false,
+		"logs/2024/01":         false,
+	} {
+		if got, _ := path.Match(glob, name); got != want {
+			t.Errorf("path.Match(%v, %v) = %v, want %v", glob, name, got, want)
+		}
+	}
+}
+
+func TestPattern_Glob_Files(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "my logs", "2024", "01", "app.log")
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	glob, err := MustCompile("{bucket}/{year}/{month}/{file}").Glob(ObjectKey{Bucket: "my logs", Year: 2024})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := filepath.Glob(filepath.Join(dir, filepath.FromSlash(glob)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{file}, got); diff != "" {
+		t.Errorf("filepath.Glob() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPattern_Glob_Errors(t *testing.T) {
+	p := MustCompile("{bucket}/{year}/{month}/{file}")
+	src := ObjectKey{Bucket: "my/logs"}
+	if got, err := p.Glob(src); err == nil {
+		t.Errorf("Glob() return (%v), which is not what we want.", got)
+	}
+	if got, err := p.Prefix(src); err == nil {
+		t.Errorf("Prefix() return (%v), which is not what we want.", got)
+	}
+}
+
+func TestPattern_Prefix(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		src     interface{}
+		want    string
+	}{
+		{
+			name:    "Up to the first zero field",
+			pattern: "{bucket}/{year}/{month}/{file}",
+			src:     ObjectKey{Bucket: "logs", Year: 2024},
+			want:    "logs/2024/",
+		},
+		{
+			name:    "Fields after a zero field are ignored",
+			pattern: "{bucket}/{year}/{month}/{file}",
+			src:     ObjectKey{Bucket: "logs", File: "app.log"},
+			want:    "logs/",
+		},
+		{
+			name:    "Literal text of a mixed segment",
+			pattern: "{bucket}/v{year}/{file}",
+			src:     ObjectKey{Bucket: "logs"},
+			want:    "logs/v",
+		},
+		{
+			name:    "Every field is set",
+			pattern: "{bucket}/{year}/{month}/{file}",
+			src:     ObjectKey{Bucket: "logs", Year: 2024, Month: 1, File: "app.log"},
+			want:    "logs/2024/1/app.log",
+		},
+		{
+			name:    "Values are not percent-encoded",
+			pattern: "{bucket}/{year}/{month}/{file}",
+			src:     ObjectKey{Bucket: "my logs*", Year: 2024},
+			want:    "my logs*/2024/",
+		},
+		{
+			name:    "No field is set",
+			pattern: "/{bucket}/{year}",
+			src:     ObjectKey{},
+			want:    "/",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MustCompile(tt.pattern).Prefix(tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Prefix() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,5 +1,14 @@
 module github.com/KamikazeZirou/path-mapper
 
-go 1.17
+go 1.22.0
 
-require github.com/google/go-cmp v0.5.6
+require (
+	github.com/google/go-cmp v0.6.0
+	golang.org/x/tools v0.26.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	golang.org/x/mod v0.21.0 // indirect
+	golang.org/x/sync v0.8.0 // indirect
+)
@@ -0,0 +1,144 @@
+// Package httprouter implements an HTTP request router that maps path parameters into structures.
+package httprouter
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+
+	mapper "github.com/KamikazeZirou/path-mapper"
+)
+
+type handle func(w http.ResponseWriter, r *http.Request, m *mapper.Match)
+
+// methodRoutes holds the patterns registered for a method, each with its handle as the value.
+type methodRoutes struct {
+	set      *mapper.PatternSet
+	patterns []*mapper.Pattern
+}
+
+// Router dispatches requests to the handler registered for the request method with the most specific pattern
+// that matches the escaped request path. A less specific pattern of the method is used when a more specific one
+// has handlers for other methods only, so "DELETE /users/me" reaches "DELETE /users/{name}" next to "GET /users/me".
+//
+// A request whose path matches no pattern is answered by NotFound.
+// A request whose path matches only patterns of other methods is answered by MethodNotAllowed.
+// A request whose path parameters cannot be mapped into the handler's structure is answered by BadRequest.
+type Router struct {
+	// NotFound handles requests whose path matches no pattern. http.NotFound is used if it is nil.
+	NotFound http.Handler
+	// MethodNotAllowed handles requests whose method has no handler.
+	// The Allow header is set before it is called.
+	// A 405 Method Not Allowed response is written if it is nil.
+	MethodNotAllowed http.Handler
+	// BadRequest handles a *mapper.BindError returned while mapping path parameters.
+	// A 400 Bad Request response is written if it is nil.
+	BadRequest func(w http.ResponseWriter, r *http.Request, err error)
+
+	methods map[string]*methodRoutes
+}
+
+// New returns a new Router.
+func New() *Router {
+	return &Router{
+		methods: make(map[string]*methodRoutes),
+	}
+}
+
+// Handle registers a handler for method and pattern.
+// It panics if pattern is invalid or a handler is already registered for method and pattern,
+// or for method and a pattern that differs from pattern only in placeholder names.
+func Handle[T any](rt *Router, method, pattern string, h func(w http.ResponseWriter, r *http.Request, params T)) {
+	rt.handle(method, pattern, func(w http.ResponseWriter, r *http.Request, m *mapper.Match) {
+		var params T
+		if err := m.Mapping(&params); err != nil {
+			rt.mappingError(w, r, err)
+			return
+		}
+		h(w, r, params)
+	})
+}
+
+// Handle registers an http.Handler that does not need path parameters for method and pattern.
+// It panics if pattern is invalid or a handler is already registered for method and pattern.
+func (rt *Router) Handle(method, pattern string, h http.Handler) {
+	rt.handle(method, pattern, func(w http.ResponseWriter, r *http.Request, _ *mapper.Match) {
+		h.ServeHTTP(w, r)
+	})
+}
+
+// handle registers h for method and pattern.
+func (rt *Router) handle(method, pattern string, h handle) {
+	p, err := mapper.Compile(pattern)
+	if err != nil {
+		panic(err)
+	}
+	routes, ok := rt.methods[method]
+	if !ok {
+		routes = &methodRoutes{set: mapper.NewPatternSet()}
+		rt.methods[method] = routes
+	}
+	for _, other := range routes.patterns {
+		if other.Equivalent(p) {
+			panic("httprouter: multiple registrations for " + method + " " + pattern + " and " + other.String())
+		}
+	}
+	routes.set.AddPattern(p, h)
+	routes.patterns = append(routes.patterns, p)
+}
+
+// ServeHTTP dispatches the request to the handler of its method whose pattern matches the request path.
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.EscapedPath()
+	if routes, ok := rt.methods[r.Method]; ok {
+		if m, ok := routes.set.Match(path); ok {
+			m.Value.(handle)(w, r, m)
+			return
+		}
+	}
+
+	allow := rt.allow(path)
+	if len(allow) == 0 {
+		if rt.NotFound != nil {
+			rt.NotFound.ServeHTTP(w, r)
+		} else {
+			http.NotFound(w, r)
+		}
+		return
+	}
+	w.Header().Set("Allow", strings.Join(allow, ", "))
+	if rt.MethodNotAllowed != nil {
+		rt.MethodNotAllowed.ServeHTTP(w, r)
+	} else {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+// allow returns the sorted methods that have a pattern matching path.
+func (rt *Router) allow(path string) []string {
+	var methods []string
+	var m mapper.Match
+	for method, routes := range rt.methods {
+		if routes.set.MatchInto(path, &m) {
+			methods = append(methods, method)
+		}
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+func (rt *Router) mappingError(w http.ResponseWriter, r *http.Request, err error) {
+	var bindErr *mapper.BindError
+	if !errors.As(err, &bindErr) {
+		// The handler's structure cannot hold path parameters, which is a programming error.
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	if rt.BadRequest != nil {
+		rt.BadRequest(w, r, err)
+	} else {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,180 @@
+package httprouter
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type GitHubIssue struct {
+	Owner      string
+	Repository string
+	Number     int
+}
+
+func newRouter() *Router {
+	rt := New()
+	Handle(rt, http.MethodGet, "/{owner}/{repository}/issues/{number}", func(w http.ResponseWriter, r *http.Request, params GitHubIssue) {
+		_, _ = fmt.Fprintf(w, "issue %v/%v#%v", params.Owner, params.Repository, params.Number)
+	})
+	Handle(rt, http.MethodPost, "/{owner}/{repository}/issues/{number}", func(w http.ResponseWriter, r *http.Request, params GitHubIssue) {
+		_, _ = fmt.Fprintf(w, "comment on %v/%v#%v", params.Owner, params.Repository, params.Number)
+	})
+	Handle(rt, http.MethodGet, "/users/{id}", func(w http.ResponseWriter, r *http.Request, params struct {
+		ID string `alias:"id"`
+	}) {
+		_, _ = fmt.Fprintf(w, "user %v", params.ID)
+	})
+	rt.Handle(http.MethodGet, "/users/me", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, "me")
+	}))
+	Handle(rt, http.MethodDelete, "/users/{name}", func(w http.ResponseWriter, r *http.Request, params struct{ Name string }) {
+		_, _ = fmt.Fprintf(w, "delete user %v", params.Name)
+	})
+	rt.Handle(http.MethodGet, "/{owner}/{repository}/{path...}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, "file")
+	}))
+	return rt
+}
+
+func TestRouter_ServeHTTP(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+		body   string
+		allow  string
+	}{
+		{
+			name:   "Typed handler",
+			method: http.MethodGet,
+			path:   "/KamikazeZirou/path-mapper/issues/1",
+			status: http.StatusOK,
+			body:   "issue KamikazeZirou/path-mapper#1",
+		},
+		{
+			name:   "Path parameters are percent-decoded",
+			method: http.MethodGet,
+			path:   "/John%20Doe/path-mapper/issues/1",
+			status: http.StatusOK,
+			body:   "issue John Doe/path-mapper#1",
+		},
+		{
+			name:   "Handler for another method",
+			method: http.MethodPost,
+			path:   "/KamikazeZirou/path-mapper/issues/1",
+			status: http.StatusOK,
+			body:   "comment on KamikazeZirou/path-mapper#1",
+		},
+		{
+			name:   "Less specific pattern",
+			method: http.MethodGet,
+			path:   "/KamikazeZirou/path-mapper/README.md",
+			status: http.StatusOK,
+			body:   "file",
+		},
+		{
+			name:   "Not found",
+			method: http.MethodGet,
+			path:   "/KamikazeZirou",
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "Method not allowed",
+			method: http.MethodDelete,
+			path:   "/KamikazeZirou/path-mapper/issues/1",
+			status: http.StatusMethodNotAllowed,
+			allow:  "GET, POST",
+		},
+		{
+			name:   "Equivalent pattern of another method",
+			method: http.MethodDelete,
+			path:   "/users/alice",
+			status: http.StatusOK,
+			body:   "delete user alice",
+		},
+		{
+			name:   "First of equivalent patterns",
+			method: http.MethodGet,
+			path:   "/users/1",
+			status: http.StatusOK,
+			body:   "user 1",
+		},
+		{
+			name:   "Method not allowed for equivalent patterns",
+			method: http.MethodPut,
+			path:   "/users/1",
+			status: http.StatusMethodNotAllowed,
+			allow:  "DELETE, GET",
+		},
+		{
+			name:   "Literal pattern",
+			method: http.MethodGet,
+			path:   "/users/me",
+			status: http.StatusOK,
+			body:   "me",
+		},
+		{
+			name:   "Placeholder pattern of a method the literal pattern lacks",
+			method: http.MethodDelete,
+			path:   "/users/me",
+			status: http.StatusOK,
+			body:   "delete user me",
+		},
+		{
+			name:   "Method not allowed for literal and placeholder patterns",
+			method: http.MethodPut,
+			path:   "/users/me",
+			status: http.StatusMethodNotAllowed,
+			allow:  "DELETE, GET",
+		},
+		{
+			name:   "Path parameter cannot be mapped",
+			method: http.MethodGet,
+			path:   "/KamikazeZirou/path-mapper/issues/abc",
+			status: http.StatusBadRequest,
+		},
+	}
+
+	rt := newRouter()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			rt.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if w.Code != tt.status {
+				t.Errorf("ServeHTTP() status = %v, want %v", w.Code, tt.status)
+			}
+			if tt.body != "" && w.Body.String() != tt.body {
+				t.Errorf("ServeHTTP() body = %q, want %q", w.Body.String(), tt.body)
+			}
+			if got := w.Header().Get("Allow"); got != tt.allow {
+				t.Errorf("ServeHTTP() Allow = %q, want %q", got, tt.allow)
+			}
+		})
+	}
+}
+
+func TestHandle_Duplicate(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Handle() does not panic")
+		}
+	}()
+
+	rt := newRouter()
+	rt.Handle(http.MethodGet, "/{owner}/{repository}/issues/{number}", http.NotFoundHandler())
+}
+
+func TestHandle_DuplicateEquivalent(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Handle() does not panic")
+		}
+	}()
+
+	rt := newRouter()
+	rt.Handle(http.MethodGet, "/users/{name}", http.NotFoundHandler())
+}
@@ -0,0 +1,165 @@
+// Package typesx resolves the fields that the mapper package maps placeholders into from go/types information,
+// for the tools that work on source code rather than on values.
+package typesx
+
+import (
+	"go/token"
+	"go/types"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// ParserType is the Parser interface of the mapper package.
+var ParserType = types.NewInterfaceType([]*types.Func{
+	types.NewFunc(token.NoPos, nil, "Parse", types.NewSignatureType(nil, nil, nil,
+		types.NewTuple(types.NewVar(token.NoPos, nil, "s", types.Typ[types.String])),
+		types.NewTuple(types.NewVar(token.NoPos, nil, "", types.NewInterfaceType(nil, nil)), types.NewVar(token.NoPos, nil, "", types.Universe.Lookup("error").Type())),
+		false)),
+}, nil).Complete()
+
+// TextMarshalerType is encoding.TextMarshaler.
+var TextMarshalerType = types.NewInterfaceType([]*types.Func{
+	types.NewFunc(token.NoPos, nil, "MarshalText", types.NewSignatureType(nil, nil, nil,
+		nil,
+		types.NewTuple(types.NewVar(token.NoPos, nil, "", types.NewSlice(types.Typ[types.Byte])), types.NewVar(token.NoPos, nil, "", types.Universe.Lookup("error").Type())),
+		false)),
+}, nil).Complete()
+
+// Convertible tells whether Mapping can assign a string to a field of type t.
+func Convertible(t types.Type) bool {
+	if types.Implements(types.NewPointer(t), ParserType) {
+		return true
+	}
+	switch u := t.Underlying().(type) {
+	case *types.Pointer:
+		return Convertible(u.Elem())
+	case *types.Basic:
+		return u.Info()&(types.IsString|types.IsInteger) != 0 && u.Kind() != types.Uintptr
+	}
+	return false
+}
+
+// Formattable tells whether Build can format a field of type t.
+func Formattable(t types.Type) bool {
+	if p, ok := t.Underlying().(*types.Pointer); ok {
+		if types.Implements(t, TextMarshalerType) {
+			return true
+		}
+		t = p.Elem()
+	}
+	if types.Implements(t, TextMarshalerType) || types.Implements(types.NewPointer(t), TextMarshalerType) {
+		return true
+	}
+	u, ok := t.Underlying().(*types.Basic)
+	return ok && u.Info()&(types.IsString|types.IsInteger) != 0 && u.Kind() != types.Uintptr
+}
+
+// Fields returns the traversal to the field of each name in the structure that t is or points to,
+// naming the fields as Mapping does: after their alias tag, or after their name with the first letter lowercased,
+// with the fields of embedded structures promoted and the fields of other structures named "parent.child".
+func Fields(t types.Type) map[string][]*types.Var {
+	type fieldInfo struct {
+		index    []*types.Var
+		name     string
+		path     string
+		embedded bool
+		// parents are the types of the fields that lead to the field, to skip recursive fields.
+		parents []types.Type
+	}
+	type queued struct {
+		st *types.Struct
+		fi *fieldInfo
+		pp string
+	}
+
+	var fields []*fieldInfo
+	queue := []queued{{st: StructOf(t), fi: &fieldInfo{}}}
+QueueLoop:
+	for len(queue) > 0 {
+		q := queue[0]
+		queue = queue[1:]
+		if n := len(q.fi.parents); n > 0 {
+			for _, parent := range q.fi.parents[:n-1] {
+				if types.Identical(parent, q.fi.parents[n-1]) {
+					continue QueueLoop
+				}
+			}
+		}
+		if q.st == nil {
+			continue
+		}
+
+		for i := 0; i < q.st.NumFields(); i++ {
+			f := q.st.Field(i)
+			tag, name := parseName(f, q.st.Tag(i))
+			if name == "-" {
+				continue
+			}
+			fi := &fieldInfo{
+				index:   append(q.fi.index[:len(q.fi.index):len(q.fi.index)], f),
+				name:    name,
+				path:    name,
+				parents: append(q.fi.parents[:len(q.fi.parents):len(q.fi.parents)], f.Type()),
+			}
+			if q.pp != "" {
+				fi.path = q.pp + "." + name
+			}
+			if !f.Exported() && !f.Anonymous() {
+				continue
+			}
+
+			if f.Anonymous() {
+				pp := q.pp
+				if tag != "" {
+					pp = fi.path
+				}
+				fi.embedded = true
+				queue = append(queue, queued{st: StructOf(f.Type()), fi: fi, pp: pp})
+			} else if s := StructOf(f.Type()); s != nil {
+				queue = append(queue, queued{st: s, fi: fi, pp: fi.path})
+			}
+			fields = append(fields, fi)
+		}
+	}
+
+	paths := make(map[string]*fieldInfo)
+	names := make(map[string][]*types.Var)
+	for _, fi := range fields {
+		if f, ok := paths[fi.path]; !ok || f.embedded {
+			paths[fi.path] = fi
+			if fi.name != "" && !fi.embedded {
+				names[fi.path] = fi.index
+			}
+		}
+	}
+	return names
+}
+
+// StructOf returns the structure that t is or points to, or nil.
+func StructOf(t types.Type) *types.Struct {
+	if p, ok := t.Underlying().(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	s, _ := t.Underlying().(*types.Struct)
+	return s
+}
+
+// parseName returns the alias tag of a field and the name the field is mapped under.
+func parseName(f *types.Var, tag string) (string, string) {
+	name := LcFirst(f.Name())
+	if !strings.Contains(tag, "alias:") {
+		return "", name
+	}
+	alias := reflect.StructTag(tag).Get("alias")
+	name, _, _ = strings.Cut(alias, ",")
+	return alias, name
+}
+
+// LcFirst lowercases the first letter of s, as the mapper of the mapper package names fields.
+func LcFirst(s string) string {
+	for i, v := range s {
+		return string(unicode.ToLower(v)) + s[i+1:]
+	}
+	return ""
+}
@@ -0,0 +1,78 @@
+// Package middleware provides HTTP middleware that maps path parameters into request contexts,
+// for use with http.ServeMux or any other router.
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	mapper "github.com/KamikazeZirou/path-mapper"
+)
+
+type contextKey[T any] struct{}
+
+// ErrorHandler handles an error returned while mapping the request path.
+type ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+
+type options struct {
+	errorHandler ErrorHandler
+}
+
+// Option configures Bind.
+type Option func(*options)
+
+// WithErrorHandler sets the handler called instead of the next handler when the request path cannot be mapped.
+func WithErrorHandler(h ErrorHandler) Option {
+	return func(o *options) {
+		o.errorHandler = h
+	}
+}
+
+// DefaultErrorHandler answers 404 Not Found to a *mapper.MismatchError,
+// 400 Bad Request to a *mapper.BindError and 500 Internal Server Error to anything else.
+func DefaultErrorHandler(w http.ResponseWriter, _ *http.Request, err error) {
+	var mismatch *mapper.MismatchError
+	var bindErr *mapper.BindError
+	switch {
+	case errors.As(err, &mismatch):
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	case errors.As(err, &bindErr):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
+
+// Bind returns middleware that maps the escaped request path into a T using pattern
+// and stores it in the request context, where FromContext retrieves it.
+// It panics if pattern is invalid.
+func Bind[T any](pattern string, opts ...Option) func(http.Handler) http.Handler {
+	p := mapper.MustCompile(pattern)
+	o := options{errorHandler: DefaultErrorHandler}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var params T
+			if err := p.Mapping(r.URL.EscapedPath(), &params); err != nil {
+				o.errorHandler(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), params)))
+		})
+	}
+}
+
+// NewContext returns a copy of ctx that carries params.
+func NewContext[T any](ctx context.Context, params T) context.Context {
+	return context.WithValue(ctx, contextKey[T]{}, params)
+}
+
+// FromContext returns the T stored in ctx by Bind, if any.
+func FromContext[T any](ctx context.Context) (T, bool) {
+	params, ok := ctx.Value(contextKey[T]{}).(T)
+	return params, ok
+}
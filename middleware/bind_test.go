@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type GitHubIssue struct {
+	Owner      string
+	Repository string
+	Number     int
+}
+
+func TestBind(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		params, ok := FromContext[GitHubIssue](r.Context())
+		if !ok {
+			http.Error(w, "no params", http.StatusInternalServerError)
+			return
+		}
+		_, _ = fmt.Fprintf(w, "%v/%v#%v", params.Owner, params.Repository, params.Number)
+	})
+
+	tests := []struct {
+		name   string
+		opts   []Option
+		path   string
+		status int
+		body   string
+	}{
+		{
+			name:   "Path parameters are stored in the context",
+			path:   "/KamikazeZirou/path-mapper/issues/1",
+			status: http.StatusOK,
+			body:   "KamikazeZirou/path-mapper#1",
+		},
+		{
+			name:   "Path does not match",
+			path:   "/KamikazeZirou/path-mapper/pulls/1",
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "Path parameter cannot be mapped",
+			path:   "/KamikazeZirou/path-mapper/issues/abc",
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "Custom error handler",
+			opts: []Option{WithErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+				http.Error(w, "custom", http.StatusTeapot)
+			})},
+			path:   "/KamikazeZirou/path-mapper/issues/abc",
+			status: http.StatusTeapot,
+			body:   "custom\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := Bind[GitHubIssue]("/{owner}/{repository}/issues/{number}", tt.opts...)(handler)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if w.Code != tt.status {
+				t.Errorf("ServeHTTP() status = %v, want %v", w.Code, tt.status)
+			}
+			if tt.body != "" && w.Body.String() != tt.body {
+				t.Errorf("ServeHTTP() body = %q, want %q", w.Body.String(), tt.body)
+			}
+		})
+	}
+}
+
+func TestFromContext_Missing(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	if _, ok := FromContext[GitHubIssue](r.Context()); ok {
+		t.Error("FromContext() found params in an empty context")
+	}
+}
@@ -0,0 +1,99 @@
+package path_mapper
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MQTTFilter is a compiled MQTT subscription filter, such as "sensors/+/{device}/#".
+//
+// "+" matches a single topic level and "#", which must be the last level, matches any number of levels,
+// including the parent level: "sensors/#" also matches "sensors".
+// Named placeholders like those of Pattern may be mixed in and are mapped into structures;
+// "{name}" matches a single level and "{name...}" the remaining levels.
+// As in MQTT, a wildcard in the first level does not match a topic that starts with "$".
+// Topics are neither percent-decoded nor normalized.
+type MQTTFilter struct {
+	raw     string
+	pattern *Pattern
+	// parent matches the parent level of a filter ending in "#". It is nil for other filters.
+	parent *Pattern
+}
+
+// CompileMQTT parses an MQTT subscription filter.
+func CompileMQTT(filter string) (*MQTTFilter, error) {
+	p, err := compile(filter, options{noDecoding: true, reserved: "+#"})
+	if err != nil {
+		return nil, err
+	}
+
+	f := &MQTTFilter{raw: filter, pattern: p}
+	for i, s := range p.segments {
+		if s.kind == mixedSegment {
+			return nil, fmt.Errorf("MQTT filter(%v): placeholder in %v must occupy an entire level", filter, s)
+		}
+		if s.kind != literalSegment {
+			continue
+		}
+		switch {
+		case s.value == "+":
+			p.segments[i] = segment{kind: placeholderSegment, sep: s.sep}
+		case s.value == "#":
+			if i != len(p.segments)-1 {
+				return nil, fmt.Errorf("MQTT filter(%v): # must be the last level", filter)
+			}
+			p.segments[i] = segment{kind: catchAllSegment, sep: s.sep}
+			if i > 0 {
+				f.parent = &Pattern{raw: filter, segments: p.segments[:i], opts: p.opts}
+			}
+		case strings.ContainsAny(s.value, "+#"):
+			return nil, fmt.Errorf("MQTT filter(%v): wildcard %v must occupy an entire level", filter, s.value)
+		}
+	}
+	return f, nil
+}
+
+// MustCompileMQTT is like CompileMQTT but panics if the filter cannot be parsed.
+func MustCompileMQTT(filter string) *MQTTFilter {
+	f, err := CompileMQTT(filter)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// String returns the source text used to compile the filter.
+func (f *MQTTFilter) String() string {
+	return f.raw
+}
+
+// Mapping maps the named placeholders of the filter into dest if topic matches the filter.
+func (f *MQTTFilter) Mapping(topic string, dest interface{}) error {
+	if strings.HasPrefix(topic, "$") && f.pattern.segments[0].kind != literalSegment {
+		return &MismatchError{Pattern: f.raw, Path: topic}
+	}
+
+	names, values, ok := f.pattern.match(topic)
+	if !ok && f.parent != nil {
+		names, values, ok = f.parent.match(topic)
+	}
+	if !ok {
+		return &MismatchError{Pattern: f.raw, Path: topic}
+	}
+	return bind(names, values, dest)
+}
+
+// Subscription returns the most specific subscription filter that matches every topic
+// the filter can map into src.
+// Named placeholders are replaced with the fields of src they are mapped into,
+// or with "+" or "#" if the field is missing, nil or the zero value of its type.
+func (f *MQTTFilter) Subscription(src interface{}) (string, error) {
+	return f.pattern.build(src, nil, nil, func(b *strings.Builder, s segment) bool {
+		if s.kind == catchAllSegment {
+			b.WriteString("#")
+		} else {
+			b.WriteString("+")
+		}
+		return true
+	})
+}
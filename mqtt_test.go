@@ -0,0 +1,177 @@
+package path_mapper
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type Reading struct {
+	Site   string
+	Device string
+	Metric string
+}
+
+func TestMQTTFilter_Mapping(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  string
+		topic   string
+		want    *Reading
+		success bool
+	}{
+		{
+			name:    "Named placeholders",
+			filter:  "sensors/{site}/{device}/temp",
+			topic:   "sensors/berlin/d1/temp",
+			want:    &Reading{Site: "berlin", Device: "d1"},
+			success: true,
+		},
+		{
+			name:    "Single-level wildcard",
+			filter:  "sensors/+/{device}/{metric}",
+			topic:   "sensors/berlin/d1/temp",
+			want:    &Reading{Device: "d1", Metric: "temp"},
+			success: true,
+		},
+		{
+			name:    "Multi-level wildcard",
+			filter:  "sensors/{site}/#",
+			topic:   "sensors/berlin/d1/temp",
+			want:    &Reading{Site: "berlin"},
+			success: true,
+		},
+		{
+			name:    "Multi-level wildcard matches the parent level",
+			filter:  "sensors/{site}/#",
+			topic:   "sensors/berlin",
+			want:    &Reading{Site: "berlin"},
+			success: true,
+		},
+		{
+			name:    "Topic is not decoded",
+			filter:  "sensors/{site}/{device}/temp",
+			topic:   "sensors/100%/d1/temp",
+			want:    &Reading{Site: "100%", Device: "d1"},
+			success: true,
+		},
+		{
+			name:    "Single-level wildcard does not match more levels",
+			filter:  "sensors/+/temp",
+			topic:   "sensors/berlin/d1/temp",
+			success: false,
+		},
+		{
+			name:    "Wildcard does not match $ topics",
+			filter:  "#",
+			topic:   "$SYS/broker/uptime",
+			success: false,
+		},
+		{
+			name:    "Literal $ topic",
+			filter:  "$SYS/{site}/#",
+			topic:   "$SYS/broker/uptime",
+			want:    &Reading{Site: "broker"},
+			success: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := &Reading{}
+			err := MustCompileMQTT(tt.filter).Mapping(tt.topic, got)
+			if (err == nil) != tt.success {
+				t.Fatalf("Mapping() return (%v), which is not what we want.", err)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Mapping() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCompileMQTT(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  string
+		success bool
+	}{
+		{name: "Wildcards", filter: "sensors/+/{device}/#", success: true},
+		{name: "# is not the last level", filter: "sensors/#/temp", success: false},
+		{name: "Wildcard in a level", filter: "sensors/site+/temp", success: false},
+		{name: "Placeholder in a level", filter: "sensors/site-{site}/temp", success: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := CompileMQTT(tt.filter); (err == nil) != tt.success {
+				t.Errorf("CompileMQTT() return (%v), which is not what we want.", err)
+			}
+		})
+	}
+}
+
+func TestMQTTFilter_Subscription(t *testing.T) {
+	type Topic struct {
+		Site   string
+		Device *string
+		Rest   string
+	}
+
+	tests := []struct {
+		name    string
+		filter  string
+		src     interface{}
+		want    string
+		success bool
+	}{
+		{
+			name:    "Unset fields become wildcards",
+			filter:  "sensors/{site}/{device}/{rest...}",
+			src:     Topic{Site: "berlin"},
+			want:    "sensors/berlin/+/#",
+			success: true,
+		},
+		{
+			name:    "Set fields",
+			filter:  "sensors/{site}/{device}/+/{rest...}",
+			src:     &Topic{Site: "berlin", Device: strAddr("d1"), Rest: "temp/celsius"},
+			want:    "sensors/berlin/d1/+/temp/celsius",
+			success: true,
+		},
+		{
+			name:    "Placeholder without a field",
+			filter:  "sensors/{site}/{unknown}",
+			src:     Topic{Site: "berlin"},
+			want:    "sensors/berlin/+",
+			success: true,
+		},
+		{
+			name:    "Value contains a wildcard",
+			filter:  "sensors/{site}/#",
+			src:     Topic{Site: "+"},
+			success: false,
+		},
+		{
+			name:    "Value contains a separator",
+			filter:  "sensors/{site}/#",
+			src:     Topic{Site: "a/b"},
+			success: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MustCompileMQTT(tt.filter).Subscription(tt.src)
+			if (err == nil) != tt.success {
+				t.Fatalf("Subscription() return (%v), which is not what we want.", err)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("Subscription() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
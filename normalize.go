@@ -0,0 +1,127 @@
+package path_mapper
+
+import "strings"
+
+// Normalization is a set of rules that clean up a path before it is matched.
+// The zero value, Strict, matches paths exactly as they are.
+type Normalization int
+
+const (
+	// Strict matches paths as they are: "/a/b/" has an empty last segment, "//a" differs from "/a"
+	// and "/a/../b" is matched literally.
+	Strict Normalization = 0
+	// IgnoreTrailingSlash removes the trailing slashes of a path other than "/",
+	// and the trailing slash of a pattern, so "/a/b/" and "/a/b" match each other.
+	IgnoreTrailingSlash Normalization = 1 << iota
+	// CollapseSlashes replaces consecutive slashes with a single one.
+	CollapseSlashes
+	// ResolveDots removes "." segments and resolves ".." segments against the preceding segment
+	// as described in RFC 3986, section 5.2.4. ".." never climbs above the root.
+	// A dot may be percent-encoded as "%2E", so "/a/%2e%2e/b" is resolved to "/b" rather than decoded into "..".
+	ResolveDots
+)
+
+// WithNormalization sets the rules that clean up a path before it is matched.
+func WithNormalization(n Normalization) Option {
+	return func(o *options) {
+		o.normalization = n
+	}
+}
+
+// NormalizePath returns the canonical form of path under n.
+// A path that differs from its canonical form can be redirected to it.
+// path is returned as is, without allocating, when it is already canonical.
+func NormalizePath(path string, n Normalization) string {
+	if n&CollapseSlashes != 0 && strings.Contains(path, "//") {
+		path = collapseSlashes(path)
+	}
+	if n&ResolveDots != 0 && hasDotSegment(path) {
+		path = resolveDots(path)
+	}
+	if n&IgnoreTrailingSlash != 0 && len(path) > 1 && strings.HasSuffix(path, "/") {
+		path = strings.TrimRight(path, "/")
+		if path == "" {
+			path = "/"
+		}
+	}
+	return path
+}
+
+func collapseSlashes(path string) string {
+	var b strings.Builder
+	b.Grow(len(path))
+	for i := 0; i < len(path); i++ {
+		if path[i] == '/' && i > 0 && path[i-1] == '/' {
+			continue
+		}
+		b.WriteByte(path[i])
+	}
+	return b.String()
+}
+
+func hasDotSegment(path string) bool {
+	for path != "" {
+		seg := path
+		if i := strings.IndexByte(path, '/'); i >= 0 {
+			seg, path = path[:i], path[i+1:]
+		} else {
+			path = ""
+		}
+		if dots(seg) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// dots returns 1 for a "." segment and 2 for a ".." segment, in which a dot may be encoded as "%2E", or 0.
+func dots(seg string) int {
+	n := 0
+	for seg != "" {
+		switch {
+		case seg[0] == '.':
+			seg = seg[1:]
+		case len(seg) >= 3 && seg[0] == '%' && seg[1] == '2' && (seg[2] == 'e' || seg[2] == 'E'):
+			seg = seg[3:]
+		default:
+			return 0
+		}
+		n++
+	}
+	if n > 2 {
+		return 0
+	}
+	return n
+}
+
+func resolveDots(path string) string {
+	segments := strings.Split(path, "/")
+	resolved := make([]string, 0, len(segments))
+	// The empty segment before the leading slash of an absolute path is the root, which ".." never removes.
+	root := 0
+	if strings.HasPrefix(path, "/") {
+		root = 1
+	}
+
+	for i, s := range segments {
+		last := i == len(segments)-1
+		switch dots(s) {
+		case 1:
+		case 2:
+			if len(resolved) > root {
+				resolved = resolved[:len(resolved)-1]
+			}
+		default:
+			resolved = append(resolved, s)
+			continue
+		}
+		// A path ending in a dot segment refers to a directory, so it keeps its trailing slash.
+		if last {
+			resolved = append(resolved, "")
+		}
+	}
+	if len(resolved) == root {
+		resolved = append(resolved, "")
+	}
+	return strings.Join(resolved, "/")
+}
@@ -0,0 +1,82 @@
+package path_mapper
+
+import "testing"
+
+func TestNormalizePath(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		n    Normalization
+		want string
+	}{
+		{name: "Strict", path: "//a/./b/../c/", n: Strict, want: "//a/./b/../c/"},
+		{name: "Trailing slash", path: "/a/b/", n: IgnoreTrailingSlash, want: "/a/b"},
+		{name: "Trailing slashes", path: "/a/b//", n: IgnoreTrailingSlash, want: "/a/b"},
+		{name: "Root keeps its slash", path: "/", n: IgnoreTrailingSlash, want: "/"},
+		{name: "Duplicate slashes", path: "//a///b/", n: CollapseSlashes, want: "/a/b/"},
+		{name: "Dot segments", path: "/a/./b/../c", n: ResolveDots, want: "/a/c"},
+		{name: "Trailing dot segment", path: "/a/b/..", n: ResolveDots, want: "/a/"},
+		{name: "Dot dot above root", path: "/../../a", n: ResolveDots, want: "/a"},
+		{name: "Only dot segments", path: "/..", n: ResolveDots, want: "/"},
+		{name: "Segments that only contain dots", path: "/a/.../b", n: ResolveDots, want: "/a/.../b"},
+		{name: "Encoded dot segments", path: "/files/a/%2E%2E/etc/%2e/passwd", n: ResolveDots, want: "/files/etc/passwd"},
+		{name: "Partly encoded dot segment", path: "/a/b/.%2e/%2E./c", n: ResolveDots, want: "/c"},
+		{name: "Segments that only contain encoded dots", path: "/a/%2e%2e%2e/b", n: ResolveDots, want: "/a/%2e%2e%2e/b"},
+		{name: "All rules", path: "//a/./b/..//c/", n: IgnoreTrailingSlash | CollapseSlashes | ResolveDots, want: "/a/c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizePath(tt.path, tt.n); got != tt.want {
+				t.Errorf("NormalizePath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithNormalization(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		path    string
+		n       Normalization
+		success bool
+	}{
+		{name: "Strict trailing slash", pattern: "/{owner}/{repository}", path: "/guest/sandbox/", n: Strict, success: false},
+		{name: "Path with trailing slash", pattern: "/{owner}/{repository}", path: "/guest/sandbox/", n: IgnoreTrailingSlash, success: true},
+		{name: "Pattern with trailing slash", pattern: "/{owner}/{repository}/", path: "/guest/sandbox", n: IgnoreTrailingSlash, success: true},
+		{name: "Pattern with trailing slashes", pattern: "/{owner}/{repository}//", path: "/guest/sandbox", n: IgnoreTrailingSlash, success: true},
+		{name: "Relative pattern with trailing slash", pattern: "{owner}/{repository}/", path: "guest/sandbox/", n: IgnoreTrailingSlash, success: true},
+		{name: "Relative path with trailing slash", pattern: "{owner}/{repository}/", path: "guest/sandbox", n: IgnoreTrailingSlash, success: true},
+		{name: "Duplicate slashes", pattern: "/{owner}/{repository}", path: "//guest//sandbox", n: CollapseSlashes, success: true},
+		{name: "Dot segments", pattern: "/{owner}/{repository}", path: "/guest/tmp/../sandbox", n: ResolveDots, success: true},
+		{name: "Encoded dot segments", pattern: "/{owner}/{repository}", path: "/guest/tmp/%2e%2e/sandbox", n: ResolveDots, success: true},
+		{name: "Encoded dot segment is not a value", pattern: "/{owner}/{repository}", path: "/guest/%2e%2e", n: ResolveDots, success: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := &GitHubIssue{}
+			err := Mapping(tt.pattern, tt.path, got, WithNormalization(tt.n))
+			if (err == nil) != tt.success {
+				t.Fatalf("Mapping() return (%v), which is not what we want.", err)
+			}
+			if err == nil && (got.Owner != "guest" || got.Repository != "sandbox") {
+				t.Errorf("Mapping() mapped %+v", got)
+			}
+		})
+	}
+}
+
+func TestPatternSet_CanonicalPath(t *testing.T) {
+	s := NewPatternSet(WithNormalization(IgnoreTrailingSlash | CollapseSlashes | ResolveDots))
+	_ = s.Add("/{owner}/{repository}/issues/{number}", nil)
+
+	m, ok := s.Match("/guest//sandbox/./issues/1/")
+	if !ok {
+		t.Fatal("Match() does not match")
+	}
+	if want := "/guest/sandbox/issues/1"; m.Path != want {
+		t.Errorf("Match() Path = %v, want %v", m.Path, want)
+	}
+}
@@ -0,0 +1,233 @@
+package path_mapper
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OpenAPIOperation is an operation whose path parameters are mapped into a structure.
+type OpenAPIOperation struct {
+	// Method is the HTTP method, such as "GET".
+	Method string
+	// Pattern is the pattern of the path, compiled with the options of the OpenAPIPaths.
+	Pattern string
+	// Params is a structure, or a pointer to one, of the type that the path parameters are mapped into.
+	Params interface{}
+	// OperationID is written as the operationId of the operation if it is not empty.
+	OperationID string
+}
+
+// OpenAPIPaths generates the paths of an OpenAPI 3 document from patterns and the structures their parameters are mapped into.
+//
+// Each placeholder becomes a required path parameter whose schema follows the type of its field:
+// strings are strings, signed and unsigned integers are integers with a format and bounds that fit the type,
+// and types that implement Parser are strings. Constraints on strings add a pattern, or an enum when they list literal values,
+// as in "{kind:issues|pulls}", and uuid adds the format uuid. A uint constraint on a signed integer adds a minimum of 0,
+// and a constraint that lists integers on an integer, as in "{kind:1|2|3}", adds an enum. Other constraints on integers
+// cannot be written as a schema and fail Add.
+type OpenAPIPaths struct {
+	opts  []Option
+	paths map[string]map[string]*openAPIOperationObject
+}
+
+type openAPIOperationObject struct {
+	OperationID string                   `json:"operationId,omitempty" yaml:"operationId,omitempty"`
+	Parameters  []openAPIParameterObject `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+}
+
+type openAPIParameterObject struct {
+	Name     string        `json:"name" yaml:"name"`
+	In       string        `json:"in" yaml:"in"`
+	Required bool          `json:"required" yaml:"required"`
+	Schema   openAPISchema `json:"schema" yaml:"schema"`
+}
+
+type openAPISchema struct {
+	Type    string        `json:"type" yaml:"type"`
+	Format  string        `json:"format,omitempty" yaml:"format,omitempty"`
+	Minimum *int64        `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+	Maximum *uint64       `json:"maximum,omitempty" yaml:"maximum,omitempty"`
+	Pattern string        `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	Enum    []interface{} `json:"enum,omitempty" yaml:"enum,omitempty"`
+}
+
+// NewOpenAPIPaths returns an empty OpenAPIPaths whose patterns are compiled with opts.
+func NewOpenAPIPaths(opts ...Option) *OpenAPIPaths {
+	return &OpenAPIPaths{opts: opts, paths: make(map[string]map[string]*openAPIOperationObject)}
+}
+
+// Add adds an operation. It fails if the pattern cannot be written as an OpenAPI path template,
+// if a placeholder is mapped into a field of a type that cannot hold it,
+// or if an operation with the same method and path template has already been added.
+func (o *OpenAPIPaths) Add(op OpenAPIOperation) error {
+	p, err := Compile(op.Pattern, o.opts...)
+	if err != nil {
+		return err
+	}
+	path, err := p.format(OpenAPI, true)
+	if err != nil {
+		return err
+	}
+
+	t := reflect.TypeOf(op.Params)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return fmt.Errorf("pattern(%v): params not a struct", op.Pattern)
+	}
+	fields := fieldMapper.TypeMap(t)
+
+	operation := &openAPIOperationObject{OperationID: op.OperationID}
+	for _, s := range p.segments {
+		parts := []segment{s}
+		if s.kind == mixedSegment {
+			parts = s.parts
+		}
+		for _, part := range parts {
+			if part.kind != placeholderSegment {
+				continue
+			}
+			schema := openAPISchema{Type: "string"}
+			if fi, ok := fields.Names[part.value]; ok {
+				if schema, err = schemaOf(fi.Field.Type); err != nil {
+					return fmt.Errorf("pattern(%v): field of %v: %w", op.Pattern, part, err)
+				}
+			}
+			if err := schema.constrain(part.constraint); err != nil {
+				return fmt.Errorf("pattern(%v): %v: %w", op.Pattern, part, err)
+			}
+			operation.Parameters = append(operation.Parameters, openAPIParameterObject{
+				Name: part.value, In: "path", Required: true, Schema: schema,
+			})
+		}
+	}
+
+	method := strings.ToLower(op.Method)
+	operations, ok := o.paths[path]
+	if !ok {
+		operations = make(map[string]*openAPIOperationObject)
+		o.paths[path] = operations
+	}
+	if _, ok := operations[method]; ok {
+		return fmt.Errorf("pattern(%v): %v %v is already added", op.Pattern, op.Method, path)
+	}
+	operations[method] = operation
+	return nil
+}
+
+// schemaOf returns the schema of the values that convertAssign can assign to a field of type t.
+func schemaOf(t reflect.Type) (openAPISchema, error) {
+	if t.Implements(parserType) || reflect.PtrTo(t).Implements(parserType) {
+		return openAPISchema{Type: "string"}, nil
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return schemaOf(t.Elem())
+	case reflect.String:
+		return openAPISchema{Type: "string"}, nil
+	case reflect.Int, reflect.Int64:
+		return openAPISchema{Type: "integer", Format: "int64"}, nil
+	case reflect.Int32:
+		return openAPISchema{Type: "integer", Format: "int32"}, nil
+	case reflect.Int8, reflect.Int16:
+		bits := t.Bits()
+		min := int64(-1) << (bits - 1)
+		max := uint64(1)<<(bits-1) - 1
+		return openAPISchema{Type: "integer", Format: "int32", Minimum: &min, Maximum: &max}, nil
+	case reflect.Uint, reflect.Uint64:
+		return openAPISchema{Type: "integer", Minimum: new(int64)}, nil
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		max := uint64(math.MaxUint64) >> (64 - t.Bits())
+		format := "int32"
+		if t.Kind() == reflect.Uint32 {
+			format = "int64"
+		}
+		return openAPISchema{Type: "integer", Format: format, Minimum: new(int64), Maximum: &max}, nil
+	}
+	return openAPISchema{}, fmt.Errorf("type %v cannot hold a path parameter", t)
+}
+
+var parserType = reflect.TypeOf((*Parser)(nil)).Elem()
+
+// constrain restricts the schema with the constraint of a placeholder.
+func (s *openAPISchema) constrain(c *constraint) error {
+	if c == nil {
+		return nil
+	}
+	if s.Type == "integer" {
+		return s.constrainInteger(c)
+	}
+	if c.text == "uuid" {
+		s.Format = "uuid"
+		return nil
+	}
+	if values, ok := literalAlternatives(c.expr); ok {
+		for _, v := range values {
+			s.Enum = append(s.Enum, v)
+		}
+		return nil
+	}
+
+	expr := c.expr
+	if strings.Contains(expr, "|") {
+		expr = "(?:" + expr + ")"
+	}
+	s.Pattern = "^" + expr + "$"
+	return nil
+}
+
+// constrainInteger restricts the schema of an integer, to which pattern does not apply.
+func (s *openAPISchema) constrainInteger(c *constraint) error {
+	switch c.text {
+	case "int":
+		// The type already restricts the value to an integer.
+		return nil
+	case "uint":
+		if s.Minimum == nil || *s.Minimum < 0 {
+			s.Minimum = new(int64)
+		}
+		return nil
+	}
+	if values, ok := literalAlternatives(c.expr); ok {
+		enum := make([]interface{}, len(values))
+		for i, v := range values {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return fmt.Errorf("constraint %v lists %v, which is not an integer", c.text, v)
+			}
+			enum[i] = n
+		}
+		s.Enum = enum
+		return nil
+	}
+	return fmt.Errorf("constraint %v cannot be written in the schema of an integer", c.text)
+}
+
+// WriteJSON writes the paths as the paths object of an OpenAPI document in JSON, such as {"paths": {...}}.
+func (o *OpenAPIPaths) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(o.document())
+}
+
+// WriteYAML writes the paths as the paths object of an OpenAPI document in YAML.
+func (o *OpenAPIPaths) WriteYAML(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(o.document()); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func (o *OpenAPIPaths) document() map[string]interface{} {
+	return map[string]interface{}{"paths": o.paths}
+}
@@ -0,0 +1,292 @@
+package path_mapper
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"strings"
+)
+
+// OpenAPIMatcher matches requests against the operations of an OpenAPI 3 document.
+type OpenAPIMatcher struct {
+	// sets holds the patterns of each method, with their operationId as value.
+	sets       map[string]*PatternSet
+	operations map[string]*Pattern
+}
+
+type openAPIDocumentIn struct {
+	Paths      map[string]*openAPIPathItemIn `json:"paths"`
+	Components struct {
+		Schemas    map[string]*openAPISchemaIn    `json:"schemas"`
+		Parameters map[string]*openAPIParameterIn `json:"parameters"`
+	} `json:"components"`
+}
+
+type openAPIPathItemIn struct {
+	Parameters []*openAPIParameterIn `json:"parameters"`
+	Get        *openAPIOperationIn   `json:"get"`
+	Put        *openAPIOperationIn   `json:"put"`
+	Post       *openAPIOperationIn   `json:"post"`
+	Delete     *openAPIOperationIn   `json:"delete"`
+	Options    *openAPIOperationIn   `json:"options"`
+	Head       *openAPIOperationIn   `json:"head"`
+	Patch      *openAPIOperationIn   `json:"patch"`
+	Trace      *openAPIOperationIn   `json:"trace"`
+}
+
+type openAPIOperationIn struct {
+	OperationID string                `json:"operationId"`
+	Parameters  []*openAPIParameterIn `json:"parameters"`
+}
+
+type openAPIParameterIn struct {
+	Ref    string           `json:"$ref"`
+	Name   string           `json:"name"`
+	In     string           `json:"in"`
+	Schema *openAPISchemaIn `json:"schema"`
+}
+
+type openAPISchemaIn struct {
+	Ref string `json:"$ref"`
+	// Type is a string, or an array of strings in OpenAPI 3.1.
+	Type    interface{}       `json:"type"`
+	Format  string            `json:"format"`
+	Pattern string            `json:"pattern"`
+	Minimum *float64          `json:"minimum"`
+	Enum    []json.RawMessage `json:"enum"`
+}
+
+// ReadOpenAPI reads an OpenAPI 3 document in JSON and compiles the path of each operation with opts.
+//
+// The schemas of path parameters become constraints: integers become int, or uint if their minimum is not negative,
+// numbers become float, booleans become "true|false", strings of the format uuid become uuid,
+// and enums and patterns become regular expressions. A pattern that is not anchored with "^" and "$"
+// may match anywhere in the value, as in JSON Schema. Local references, such as "#/components/schemas/Id", are resolved.
+// Paths are matched as they are written in the document, without the path of a server URL.
+func ReadOpenAPI(r io.Reader, opts ...Option) (*OpenAPIMatcher, error) {
+	var doc openAPIDocumentIn
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("OpenAPI document: %w", err)
+	}
+
+	// Paths are written in the syntax of this package, whatever dialect opts give.
+	opts = append(opts[:len(opts):len(opts)], WithDialect(Native))
+	m := &OpenAPIMatcher{sets: make(map[string]*PatternSet), operations: make(map[string]*Pattern)}
+
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		item := doc.Paths[path]
+		for _, op := range []struct {
+			method string
+			op     *openAPIOperationIn
+		}{
+			{"GET", item.Get}, {"PUT", item.Put}, {"POST", item.Post}, {"DELETE", item.Delete},
+			{"OPTIONS", item.Options}, {"HEAD", item.Head}, {"PATCH", item.Patch}, {"TRACE", item.Trace},
+		} {
+			if op.op == nil {
+				continue
+			}
+			if err := m.add(&doc, path, op.method, item, op.op, opts); err != nil {
+				return nil, fmt.Errorf("OpenAPI document: %v %v: %w", op.method, path, err)
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m *OpenAPIMatcher) add(doc *openAPIDocumentIn, path, method string, item *openAPIPathItemIn, op *openAPIOperationIn, opts []Option) error {
+	// Parameters of the operation override those of the path item.
+	constraints := make(map[string]string)
+	for _, params := range [][]*openAPIParameterIn{item.Parameters, op.Parameters} {
+		for _, param := range params {
+			param, err := doc.parameter(param)
+			if err != nil {
+				return err
+			}
+			if param.In != "path" {
+				continue
+			}
+			schema, err := doc.schema(param.Schema)
+			if err != nil {
+				return err
+			}
+			if constraints[param.Name], err = schema.constraint(); err != nil {
+				return fmt.Errorf("parameter %v: %w", param.Name, err)
+			}
+		}
+	}
+
+	pattern, err := constrainTemplate(path, constraints)
+	if err != nil {
+		return err
+	}
+	p, err := Compile(pattern, opts...)
+	if err != nil {
+		return err
+	}
+
+	if op.OperationID != "" {
+		if _, ok := m.operations[op.OperationID]; ok {
+			return fmt.Errorf("duplicate operationId %v", op.OperationID)
+		}
+		m.operations[op.OperationID] = p
+	}
+	set, ok := m.sets[method]
+	if !ok {
+		set = NewPatternSet(opts...)
+		m.sets[method] = set
+	}
+	set.AddPattern(p, op.OperationID)
+	return nil
+}
+
+func (doc *openAPIDocumentIn) parameter(param *openAPIParameterIn) (*openAPIParameterIn, error) {
+	for seen := 0; param != nil && param.Ref != ""; seen++ {
+		name, ok := strings.CutPrefix(param.Ref, "#/components/parameters/")
+		if !ok || seen > len(doc.Components.Parameters) {
+			return nil, fmt.Errorf("unsupported reference %v", param.Ref)
+		}
+		if param, ok = doc.Components.Parameters[name]; !ok {
+			return nil, fmt.Errorf("unresolved reference #/components/parameters/%v", name)
+		}
+	}
+	if param == nil {
+		return nil, fmt.Errorf("null parameter")
+	}
+	return param, nil
+}
+
+func (doc *openAPIDocumentIn) schema(schema *openAPISchemaIn) (*openAPISchemaIn, error) {
+	for seen := 0; schema != nil && schema.Ref != ""; seen++ {
+		name, ok := strings.CutPrefix(schema.Ref, "#/components/schemas/")
+		if !ok || seen > len(doc.Components.Schemas) {
+			return nil, fmt.Errorf("unsupported reference %v", schema.Ref)
+		}
+		if schema, ok = doc.Components.Schemas[name]; !ok {
+			return nil, fmt.Errorf("unresolved reference #/components/schemas/%v", name)
+		}
+	}
+	if schema == nil {
+		return &openAPISchemaIn{}, nil
+	}
+	return schema, nil
+}
+
+// constraint translates the schema into the constraint of a placeholder, or "" if it accepts any value.
+func (s *openAPISchemaIn) constraint() (string, error) {
+	if s.Enum != nil {
+		values := make([]string, len(s.Enum))
+		for i, raw := range s.Enum {
+			var value string
+			if err := json.Unmarshal(raw, &value); err != nil {
+				value = string(raw)
+			}
+			values[i] = regexp.QuoteMeta(value)
+		}
+		return strings.Join(values, "|"), nil
+	}
+
+	switch s.typeName() {
+	case "integer":
+		if s.Minimum != nil && *s.Minimum >= 0 {
+			return "uint", nil
+		}
+		return "int", nil
+	case "number":
+		return "float", nil
+	case "boolean":
+		return "true|false", nil
+	}
+	if s.Format == "uuid" {
+		return "uuid", nil
+	}
+	if s.Pattern == "" {
+		return "", nil
+	}
+
+	re, err := syntax.Parse(s.Pattern, syntax.Perl)
+	if err != nil {
+		return "", fmt.Errorf("pattern %v: %w", s.Pattern, err)
+	}
+	// A schema pattern matches anywhere in the value unless it is anchored, while a constraint matches all of it.
+	// Only anchors around the whole pattern can be dropped, and the rest is grouped so that an alternation stays whole.
+	expr, prefix, suffix := s.Pattern, ".*", ".*"
+	if re.Op == syntax.OpConcat && len(re.Sub) > 1 {
+		if re.Sub[0].Op == syntax.OpBeginText && strings.HasPrefix(expr, "^") {
+			expr, prefix = expr[1:], ""
+		}
+		if re.Sub[len(re.Sub)-1].Op == syntax.OpEndText && strings.HasSuffix(expr, "$") && !strings.HasSuffix(expr, `\$`) {
+			expr, suffix = expr[:len(expr)-1], ""
+		}
+	}
+	if prefix != "" || suffix != "" {
+		expr = prefix + "(?:" + expr + ")" + suffix
+	}
+	if _, err := regexp.Compile(expr); err != nil {
+		return "", fmt.Errorf("pattern %v: %w", s.Pattern, err)
+	}
+	return expr, nil
+}
+
+// typeName returns the type of the schema, ignoring "null" in the types of OpenAPI 3.1.
+func (s *openAPISchemaIn) typeName() string {
+	switch t := s.Type.(type) {
+	case string:
+		return t
+	case []interface{}:
+		for _, t := range t {
+			if name, ok := t.(string); ok && name != "null" {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// constrainTemplate translates an OpenAPI path template into a pattern, adding the constraint of each parameter.
+func constrainTemplate(path string, constraints map[string]string) (string, error) {
+	if _, err := TranslatePattern(path, OpenAPI); err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for rest := path; rest != ""; {
+		i := strings.IndexByte(rest, '{')
+		if i < 0 {
+			b.WriteString(rest)
+			break
+		}
+		end := strings.IndexByte(rest, '}')
+		name := rest[i+1 : end]
+		b.WriteString(rest[:i])
+		if c := constraints[name]; c != "" {
+			fmt.Fprintf(&b, "{%v:%v}", name, c)
+		} else {
+			fmt.Fprintf(&b, "{%v}", name)
+		}
+		rest = rest[end+1:]
+	}
+	return b.String(), nil
+}
+
+// Match returns the most specific pattern of the operations of method that matches path.
+// Match.Value is the operationId of the operation, which is empty if the document gives none.
+func (m *OpenAPIMatcher) Match(method, path string) (*Match, bool) {
+	set, ok := m.sets[strings.ToUpper(method)]
+	if !ok {
+		return nil, false
+	}
+	return set.Match(path)
+}
+
+// Pattern returns the pattern of the operation with operationID.
+func (m *OpenAPIMatcher) Pattern(operationID string) (*Pattern, bool) {
+	p, ok := m.operations[operationID]
+	return p, ok
+}
@@ -0,0 +1,178 @@
+package path_mapper
+
+import (
+	"bytes"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const openAPIDocument = `{
+  "openapi": "3.1.0",
+  "paths": {
+    "/repos/{owner}/{kind}/{number}": {
+      "parameters": [
+        {"name": "owner", "in": "path", "required": true, "schema": {"type": "string", "pattern": "^[A-Za-z0-9-]+$"}},
+        {"$ref": "#/components/parameters/Kind"}
+      ],
+      "get": {
+        "operationId": "getIssue",
+        "parameters": [
+          {"name": "number", "in": "path", "required": true, "schema": {"$ref": "#/components/schemas/Number"}},
+          {"name": "page", "in": "query", "schema": {"type": "integer"}}
+        ]
+      },
+      "delete": {
+        "operationId": "deleteIssue",
+        "parameters": [
+          {"name": "number", "in": "path", "required": true, "schema": {"type": ["integer", "null"]}}
+        ]
+      }
+    },
+    "/repos/{owner}/issues/mine": {
+      "get": {"operationId": "getMyIssues"}
+    },
+    "/files/{id}.{ext}": {
+      "get": {
+        "operationId": "getFile",
+        "parameters": [
+          {"name": "id", "in": "path", "required": true, "schema": {"type": "string", "format": "uuid"}},
+          {"name": "ext", "in": "path", "required": true, "schema": {"type": "string", "pattern": "json"}}
+        ]
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "Number": {"type": "integer", "format": "int32", "minimum": 1}
+    },
+    "parameters": {
+      "Kind": {"name": "kind", "in": "path", "required": true, "schema": {"type": "string", "enum": ["issues", "pulls"]}}
+    }
+  }
+}`
+
+func TestReadOpenAPI(t *testing.T) {
+	m, err := ReadOpenAPI(strings.NewReader(openAPIDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		method string
+		path   string
+		want   string
+		params []Param
+		ok     bool
+	}{
+		{method: "GET", path: "/repos/a/issues/1", want: "getIssue", params: []Param{{"owner", "a"}, {"kind", "issues"}, {"number", "1"}}, ok: true},
+		{method: "delete", path: "/repos/a/pulls/-1", want: "deleteIssue", params: []Param{{"owner", "a"}, {"kind", "pulls"}, {"number", "-1"}}, ok: true},
+		{method: "GET", path: "/repos/a/pulls/-1", ok: false},
+		{method: "GET", path: "/repos/a/commits/1", ok: false},
+		{method: "GET", path: "/repos/a_b/issues/1", ok: false},
+		{method: "GET", path: "/repos/a/issues/mine", want: "getMyIssues", params: []Param{{"owner", "a"}}, ok: true},
+		{method: "GET", path: "/files/123e4567-e89b-12d3-a456-426614174000.jsonl", want: "getFile",
+			params: []Param{{"id", "123e4567-e89b-12d3-a456-426614174000"}, {"ext", "jsonl"}}, ok: true},
+		{method: "GET", path: "/files/1.json", ok: false},
+		{method: "POST", path: "/repos/a/issues/1", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			got, ok := m.Match(tt.method, tt.path)
+			if ok != tt.ok {
+				t.Fatalf("Match() return (%v), which is not what we want.", ok)
+			}
+			if !ok {
+				return
+			}
+			if got.Value != tt.want {
+				t.Errorf("Match().Value = %v, want %v", got.Value, tt.want)
+			}
+			if diff := cmp.Diff(tt.params, got.Params); diff != "" {
+				t.Errorf("Match().Params mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	p, ok := m.Pattern("getIssue")
+	if !ok || p.String() != "/repos/{owner:[A-Za-z0-9-]+}/{kind:issues|pulls}/{number:uint}" {
+		t.Errorf("Pattern() = %v, %v, which is not what we want.", p, ok)
+	}
+}
+
+func TestReadOpenAPI_Errors(t *testing.T) {
+	for _, doc := range []string{
+		`{"paths": {"/a/{id}": {"get": {"parameters": [{"$ref": "#/components/parameters/Missing"}]}}}}`,
+		`{"paths": {"/a/{id}": {"get": {"parameters": [{"name": "id", "in": "path", "schema": {"$ref": "other.json#/Id"}}]}}}}`,
+		`{"paths": {"/a/{id}": {"get": {"parameters": [{"name": "id", "in": "path", "schema": {"type": "string", "pattern": "(?<=a)b"}}]}}}}`,
+		`{"paths": {"/a/{id}": {"get": {"operationId": "a"}}, "/b": {"get": {"operationId": "a"}}}}`,
+		`{"paths": {"/a/{id:int}": {"get": {}}}}`,
+		`{"paths": []}`,
+	} {
+		if _, err := ReadOpenAPI(strings.NewReader(doc)); err == nil {
+			t.Errorf("ReadOpenAPI(%v) return (%v), which is not what we want.", doc, err)
+		}
+	}
+}
+
+func TestReadOpenAPI_RoundTrip(t *testing.T) {
+	paths := NewOpenAPIPaths()
+	if err := paths.Add(OpenAPIOperation{Method: "GET", Pattern: "/repos/{owner}/{kind:issues|pulls}/{number:uint}/{id:uuid}", Params: OpenAPIIssue{}, OperationID: "getIssue"}); err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err := paths.WriteJSON(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := ReadOpenAPI(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p, ok := m.Pattern("getIssue"); !ok || p.String() != "/repos/{owner}/{kind:issues|pulls}/{number:uint}/{id:uuid}" {
+		t.Errorf("Pattern() = %v, %v, which is not what we want.", p, ok)
+	}
+}
+
+func TestReadOpenAPI_Pattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		value   string
+		ok      bool
+	}{
+		{pattern: "json", value: "jsonl", ok: true},
+		{pattern: "json", value: "yaml", ok: false},
+		{pattern: "a|b", value: "xay", ok: true},
+		{pattern: "a|b", value: "xby", ok: true},
+		{pattern: "a|b", value: "xy", ok: false},
+		{pattern: "^a|b$", value: "ax", ok: true},
+		{pattern: "^a|b$", value: "xb", ok: true},
+		{pattern: "^a|b$", value: "xa", ok: false},
+		{pattern: "^(a|b)$", value: "a", ok: true},
+		{pattern: "^(a|b)$", value: "ab", ok: false},
+		{pattern: "^a|^b", value: "bx", ok: true},
+		{pattern: "^a|^b", value: "xb", ok: false},
+		{pattern: "^ab", value: "abc", ok: true},
+		{pattern: "^ab", value: "cab", ok: false},
+		{pattern: `a\$`, value: "xa$", ok: true},
+		{pattern: `a\$`, value: "a$x", ok: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.value, func(t *testing.T) {
+			doc := `{"paths": {"/a/{id}": {"get": {"operationId": "a", "parameters": [{"name": "id", "in": "path", "schema": {"type": "string", "pattern": ` +
+				strconv.Quote(tt.pattern) + `}}]}}}}`
+			m, err := ReadOpenAPI(strings.NewReader(doc))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := m.Match("GET", "/a/"+url.PathEscape(tt.value)); ok != tt.ok {
+				t.Errorf("Match() return (%v), which is not what we want.", ok)
+			}
+		})
+	}
+}
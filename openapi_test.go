@@ -0,0 +1,248 @@
+package path_mapper
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type OpenAPIIssue struct {
+	Owner  string
+	Kind   string
+	Number int32
+	Day    uint8
+	Offset int `alias:"offset"`
+	ID     string
+}
+
+func TestOpenAPIPaths_WriteJSON(t *testing.T) {
+	paths := NewOpenAPIPaths()
+	for _, op := range []OpenAPIOperation{
+		{Method: "GET", Pattern: "/repos/{owner}/{kind:issues|pulls}/{number}", Params: OpenAPIIssue{}, OperationID: "getIssue"},
+		{Method: "DELETE", Pattern: "/repos/{owner}/{kind:issues|pulls}/{number:uint}", Params: &OpenAPIIssue{}},
+		{Method: "GET", Pattern: "/logs/{day}.log/{offset:uint}/{id:uuid}/{name:[a-z]+}", Params: OpenAPIIssue{}},
+	} {
+		if err := paths.Add(op); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var b bytes.Buffer
+	if err := paths.WriteJSON(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "paths": {
+    "/logs/{day}.log/{offset}/{id}/{name}": {
+      "get": {
+        "parameters": [
+          {
+            "name": "day",
+            "in": "path",
+            "required": true,
+            "schema": {
+              "type": "integer",
+              "format": "int32",
+              "minimum": 0,
+              "maximum": 255
+            }
+          },
+          {
+            "name": "offset",
+            "in": "path",
+            "required": true,
+            "schema": {
+              "type": "integer",
+              "format": "int64",
+              "minimum": 0
+            }
+          },
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "schema": {
+              "type": "string",
+              "format": "uuid"
+            }
+          },
+          {
+            "name": "name",
+            "in": "path",
+            "required": true,
+            "schema": {
+              "type": "string",
+              "pattern": "^[a-z]+$"
+            }
+          }
+        ]
+      }
+    },
+    "/repos/{owner}/{kind}/{number}": {
+      "delete": {
+        "parameters": [
+          {
+            "name": "owner",
+            "in": "path",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          },
+          {
+            "name": "kind",
+            "in": "path",
+            "required": true,
+            "schema": {
+              "type": "string",
+              "enum": [
+                "issues",
+                "pulls"
+              ]
+            }
+          },
+          {
+            "name": "number",
+            "in": "path",
+            "required": true,
+            "schema": {
+              "type": "integer",
+              "format": "int32",
+              "minimum": 0
+            }
+          }
+        ]
+      },
+      "get": {
+        "operationId": "getIssue",
+        "parameters": [
+          {
+            "name": "owner",
+            "in": "path",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          },
+          {
+            "name": "kind",
+            "in": "path",
+            "required": true,
+            "schema": {
+              "type": "string",
+              "enum": [
+                "issues",
+                "pulls"
+              ]
+            }
+          },
+          {
+            "name": "number",
+            "in": "path",
+            "required": true,
+            "schema": {
+              "type": "integer",
+              "format": "int32"
+            }
+          }
+        ]
+      }
+    }
+  }
+}
+`
+	if diff := cmp.Diff(want, b.String()); diff != "" {
+		t.Errorf("WriteJSON() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestOpenAPIPaths_WriteYAML(t *testing.T) {
+	paths := NewOpenAPIPaths(WithDialect(Flask))
+	if err := paths.Add(OpenAPIOperation{Method: "GET", Pattern: "/users/<int:number>", Params: OpenAPIIssue{}, OperationID: "getUser"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	if err := paths.WriteYAML(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `paths:
+  /users/{number}:
+    get:
+      operationId: getUser
+      parameters:
+        - name: number
+          in: path
+          required: true
+          schema:
+            type: integer
+            format: int32
+            minimum: 0
+`
+	if diff := cmp.Diff(want, b.String()); diff != "" {
+		t.Errorf("WriteYAML() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestOpenAPIPaths_IntegerEnum(t *testing.T) {
+	paths := NewOpenAPIPaths()
+	if err := paths.Add(OpenAPIOperation{Method: "GET", Pattern: "/days/{day:1|2|3}", Params: OpenAPIIssue{}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	if err := paths.WriteYAML(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `paths:
+  /days/{day}:
+    get:
+      parameters:
+        - name: day
+          in: path
+          required: true
+          schema:
+            type: integer
+            format: int32
+            minimum: 0
+            maximum: 255
+            enum:
+              - 1
+              - 2
+              - 3
+`
+	if diff := cmp.Diff(want, b.String()); diff != "" {
+		t.Errorf("WriteYAML() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+type OpenAPIUnsupported struct {
+	Ratio float64
+}
+
+func TestOpenAPIPaths_Add(t *testing.T) {
+	tests := []struct {
+		name string
+		op   OpenAPIOperation
+	}{
+		{name: "Catch-all", op: OpenAPIOperation{Method: "GET", Pattern: "/files/{path...}", Params: OpenAPIIssue{}}},
+		{name: "Unsupported field type", op: OpenAPIOperation{Method: "GET", Pattern: "/ratios/{ratio}", Params: OpenAPIUnsupported{}}},
+		{name: "Regular expression on an integer", op: OpenAPIOperation{Method: "GET", Pattern: "/years/{day:[0-9]{4}}", Params: OpenAPIIssue{}}},
+		{name: "Non-integer values on an integer", op: OpenAPIOperation{Method: "GET", Pattern: "/days/{day:1|first}", Params: OpenAPIIssue{}}},
+		{name: "Params not a struct", op: OpenAPIOperation{Method: "GET", Pattern: "/issues/{number}", Params: 1}},
+		{name: "Duplicate operation", op: OpenAPIOperation{Method: "get", Pattern: "/issues/{number:int}", Params: OpenAPIIssue{}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paths := NewOpenAPIPaths()
+			if err := paths.Add(OpenAPIOperation{Method: "GET", Pattern: "/issues/{number}", Params: OpenAPIIssue{}}); err != nil {
+				t.Fatal(err)
+			}
+			if err := paths.Add(tt.op); err == nil {
+				t.Errorf("Add() return (%v), which is not what we want.", err)
+			}
+		})
+	}
+}
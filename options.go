@@ -0,0 +1,85 @@
+package path_mapper
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+type options struct {
+	noDecoding    bool
+	normalization Normalization
+	literalCase   LiteralCase
+	seps          string
+	// reserved holds the characters that a value must not contain when a path is built without encoding.
+	reserved string
+	dialect  Dialect
+}
+
+// Option configures how a pattern matches paths.
+type Option func(*options)
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithoutDecoding disables percent-decoding.
+// Literal segments are compared with the raw path segments, and captured values keep their escapes.
+func WithoutDecoding() Option {
+	return func(o *options) {
+		o.noDecoding = true
+	}
+}
+
+// WithSeparators sets the characters that separate segments, instead of "/".
+// Each separator is a single ASCII character other than a brace,
+// so that patterns such as "user:{id}:profile" or "arn:aws:s3:::{bucket}/{key...}" can be used for keys that are not paths.
+// A pattern only matches a path whose separators are the same as its own.
+// Normalization assumes "/" as the separator.
+func WithSeparators(seps string) Option {
+	return func(o *options) {
+		o.seps = seps
+	}
+}
+
+func (o *options) separators() string {
+	if o.seps == "" {
+		return "/"
+	}
+	return o.seps
+}
+
+// indexSeparator returns the index of the first separator in s, or -1.
+func (o *options) indexSeparator(s string) int {
+	if o.seps == "" {
+		return strings.IndexByte(s, '/')
+	}
+	return strings.IndexAny(s, o.seps)
+}
+
+func (o *options) validateSeparators() error {
+	for i := 0; i < len(o.seps); i++ {
+		if c := o.seps[i]; c == 0 || c >= utf8.RuneSelf || c == '{' || c == '}' {
+			return fmt.Errorf("invalid separator %q", c)
+		}
+	}
+	return nil
+}
+
+// decode percent-decodes a segment, or the rest of a path for a catch-all.
+// It reports false if s contains an invalid escape.
+func (o *options) decode(s string) (string, bool) {
+	if o.noDecoding || strings.IndexByte(s, '%') < 0 {
+		return s, true
+	}
+	decoded, err := url.PathUnescape(s)
+	if err != nil {
+		return "", false
+	}
+	return decoded, true
+}
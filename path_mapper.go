@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
-	"strings"
 	"unicode"
 
 	"github.com/KamikazeZirou/path-mapper/internal/reflectx"
@@ -15,6 +14,9 @@ type Parser interface {
 	Parse(s string) (interface{}, error)
 }
 
+// fieldMapper names a field after its alias tag, or after its name with the first letter lowercased.
+var fieldMapper = reflectx.NewMapperFunc("alias", lcFirst)
+
 func lcFirst(s string) string {
 	for i, v := range s {
 		return string(unicode.ToLower(v)) + s[i+1:]
@@ -24,27 +26,27 @@ func lcFirst(s string) string {
 
 // Mapping a URL or other path to a structure.
 //goland:noinspection GoUnusedExportedFunction
-func Mapping(pattern, path string, dest interface{}) error {
-	patternSegments := strings.Split(pattern, "/")
-	pathSegments := strings.Split(path, "/")
-	if len(pathSegments) != len(patternSegments) {
-		return fmt.Errorf("pattern(%value) does not match path(%value)", pattern, path)
+func Mapping(pattern, path string, dest interface{}, opts ...Option) error {
+	p, err := Compile(pattern, opts...)
+	if err != nil {
+		return err
 	}
+	return p.Mapping(path, dest)
+}
 
-	patterns := make([]string, 0, len(patternSegments))
-	values := make([]string, 0, len(patternSegments))
-	for i := 0; i < len(patternSegments); i++ {
-		patternSegment := patternSegments[i]
-		pathSegment := pathSegments[i]
-
-		if strings.HasPrefix(patternSegment, "{") && strings.HasSuffix(patternSegment, "}") {
-			patterns = append(patterns, patternSegment[1:len(patternSegment)-1])
-			values = append(values, pathSegment)
-		} else if pathSegment != patternSegment {
-			return fmt.Errorf("pattern(%value) does not match path(%value)", pattern, path)
-		}
+// MatchPrefix maps the leading segments of path that match pattern into dest, and returns the rest of the path.
+// See Pattern.MatchPrefix.
+//goland:noinspection GoUnusedExportedFunction
+func MatchPrefix(pattern, path string, dest interface{}, opts ...Option) (rest string, err error) {
+	p, err := Compile(pattern, opts...)
+	if err != nil {
+		return "", err
 	}
+	return p.MatchPrefix(path, dest)
+}
 
+// bind assigns each value to the field of dest named after the corresponding placeholder.
+func bind(patterns, values []string, dest interface{}) error {
 	v := reflect.ValueOf(dest)
 
 	if v.Kind() != reflect.Ptr {
@@ -55,8 +57,7 @@ func Mapping(pattern, path string, dest interface{}) error {
 		return errors.New("must pass non-nil pointer to dest")
 	}
 
-	m := reflectx.NewMapperFunc("alias", lcFirst)
-	traversals := m.TraversalsByName(v.Type(), patterns)
+	traversals := fieldMapper.TraversalsByName(v.Type(), patterns)
 	fields := make([]interface{}, len(patterns))
 	if err := fieldsByTraversal(v, traversals, fields, true); err != nil {
 		return err
@@ -69,7 +70,7 @@ func Mapping(pattern, path string, dest interface{}) error {
 		}
 
 		if err := convertAssign(value, fields[i]); err != nil {
-			return fmt.Errorf("failed mapping %v into %v : %w", value, fields[i], err)
+			return &BindError{Name: patterns[i], Value: value, Err: err}
 		}
 	}
 
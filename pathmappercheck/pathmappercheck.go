@@ -0,0 +1,267 @@
+// Package pathmappercheck defines an analyzer that checks constant patterns against the structures they are mapped into.
+//
+// It reports invalid patterns given to Compile, MustCompile, Mapping, MatchPrefix and Build, and for the calls of
+// Mapping and MatchPrefix, of the functions or of the methods of a pattern compiled from a constant in the same function
+// or package, it reports
+//
+//   - destinations that are not pointers to structures,
+//   - placeholders that no field is named after, which Mapping leaves unmapped, and
+//   - fields of types that Mapping cannot assign, such as float64.
+//
+// Calls of Build are checked likewise for structures, placeholders without fields, which include an unnamed catch-all,
+// and fields that Build cannot format.
+// Calls with options are not checked, since options may change the syntax of patterns.
+package pathmappercheck
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+
+	mapper "github.com/KamikazeZirou/path-mapper"
+	"github.com/KamikazeZirou/path-mapper/internal/typesx"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const mapperPath = "github.com/KamikazeZirou/path-mapper"
+
+// Analyzer checks constant patterns against the structures they are mapped into.
+var Analyzer = &analysis.Analyzer{
+	Name:     "pathmappercheck",
+	Doc:      "check constant path patterns against the structures they are mapped into",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	patterns := patternVars(pass, inspect)
+
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+		if !ok || fn.Pkg() == nil || fn.Pkg().Path() != mapperPath {
+			return
+		}
+
+		sig := fn.Type().(*types.Signature)
+		if recv := sig.Recv(); recv != nil {
+			if !isPattern(recv.Type()) {
+				return
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return
+			}
+			p, ok := patternOf(pass, patterns, sel.X)
+			if !ok {
+				return
+			}
+			switch fn.Name() {
+			case "Mapping", "MatchPrefix":
+				checkMapping(pass, fn.Name(), p, call.Args[1])
+			case "Build":
+				checkBuild(pass, p, call.Args[0])
+			}
+			return
+		}
+
+		switch fn.Name() {
+		case "Compile", "MustCompile":
+			if len(call.Args) == 1 {
+				compile(pass, call.Args[0])
+			}
+		case "Mapping", "MatchPrefix":
+			if len(call.Args) == 3 {
+				if p, ok := compile(pass, call.Args[0]); ok {
+					checkMapping(pass, fn.Name(), p, call.Args[2])
+				}
+			}
+		case "Build":
+			if len(call.Args) == 2 {
+				if p, ok := compile(pass, call.Args[0]); ok {
+					checkBuild(pass, p, call.Args[1])
+				}
+			}
+		}
+	})
+	return nil, nil
+}
+
+// compile compiles expr if it is a constant, reporting an invalid pattern.
+func compile(pass *analysis.Pass, expr ast.Expr) (*mapper.Pattern, bool) {
+	tv, ok := pass.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return nil, false
+	}
+	p, err := mapper.Compile(constant.StringVal(tv.Value))
+	if err != nil {
+		pass.Reportf(expr.Pos(), "invalid pattern: %v", err)
+		return nil, false
+	}
+	return p, true
+}
+
+// compileCall compiles the pattern of a call of Compile or MustCompile without options.
+func compileCall(pass *analysis.Pass, expr ast.Expr) (*mapper.Pattern, bool) {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return nil, false
+	}
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != mapperPath || fn.Name() != "Compile" && fn.Name() != "MustCompile" {
+		return nil, false
+	}
+	tv, ok := pass.TypesInfo.Types[call.Args[0]]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return nil, false
+	}
+	// Invalid patterns are reported where the call is visited.
+	p, err := mapper.Compile(constant.StringVal(tv.Value))
+	if err != nil {
+		return nil, false
+	}
+	return p, true
+}
+
+// patternVars returns the patterns of the variables that are assigned once, with a pattern compiled from a constant.
+func patternVars(pass *analysis.Pass, inspect *inspector.Inspector) map[*types.Var]*mapper.Pattern {
+	patterns := make(map[*types.Var]*mapper.Pattern)
+	assigned := make(map[*types.Var]int)
+	assign := func(lhs []*ast.Ident, rhs []ast.Expr) {
+		for i, id := range lhs {
+			v, ok := pass.TypesInfo.ObjectOf(id).(*types.Var)
+			if !ok {
+				continue
+			}
+			assigned[v]++
+			var value ast.Expr
+			switch {
+			case len(rhs) == len(lhs):
+				value = rhs[i]
+			case len(rhs) == 1 && i == 0:
+				// A call of Compile assigns its pattern and error to two variables.
+				value = rhs[0]
+			default:
+				continue
+			}
+			if p, ok := compileCall(pass, value); ok {
+				patterns[v] = p
+			}
+		}
+	}
+
+	inspect.Preorder([]ast.Node{(*ast.ValueSpec)(nil), (*ast.AssignStmt)(nil), (*ast.UnaryExpr)(nil)}, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.ValueSpec:
+			assign(n.Names, n.Values)
+		case *ast.AssignStmt:
+			var lhs []*ast.Ident
+			for _, expr := range n.Lhs {
+				if id, ok := ast.Unparen(expr).(*ast.Ident); ok {
+					lhs = append(lhs, id)
+				} else {
+					lhs = append(lhs, ast.NewIdent("_"))
+				}
+			}
+			assign(lhs, n.Rhs)
+		case *ast.UnaryExpr:
+			// A variable whose address is taken may be assigned through the pointer.
+			if id, ok := ast.Unparen(n.X).(*ast.Ident); ok && n.Op == token.AND {
+				if v, ok := pass.TypesInfo.ObjectOf(id).(*types.Var); ok {
+					assigned[v] += 2
+				}
+			}
+		}
+	})
+	for v := range patterns {
+		if assigned[v] != 1 {
+			delete(patterns, v)
+		}
+	}
+	return patterns
+}
+
+// patternOf returns the pattern that expr evaluates to, if it is known.
+func patternOf(pass *analysis.Pass, patterns map[*types.Var]*mapper.Pattern, expr ast.Expr) (*mapper.Pattern, bool) {
+	if id, ok := ast.Unparen(expr).(*ast.Ident); ok {
+		v, ok := pass.TypesInfo.ObjectOf(id).(*types.Var)
+		if !ok {
+			return nil, false
+		}
+		p, ok := patterns[v]
+		return p, ok
+	}
+	return compileCall(pass, expr)
+}
+
+// isPattern tells whether t is Pattern or a pointer to it.
+func isPattern(t types.Type) bool {
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == mapperPath && named.Obj().Name() == "Pattern"
+}
+
+// checkMapping reports the problems of mapping p into dest with the function or method name.
+func checkMapping(pass *analysis.Pass, name string, p *mapper.Pattern, dest ast.Expr) {
+	t := pass.TypesInfo.TypeOf(dest)
+	if t == nil || types.IsInterface(t) {
+		return
+	}
+	ptr, ok := t.Underlying().(*types.Pointer)
+	if !ok || typesx.StructOf(t) == nil {
+		pass.Reportf(dest.Pos(), "%v needs a pointer to a structure, not %v", name, typeString(pass, t))
+		return
+	}
+	checkFields(pass, name, p, dest, ptr.Elem(), typesx.Convertible)
+}
+
+// checkBuild reports the problems of building a path with p from src.
+func checkBuild(pass *analysis.Pass, p *mapper.Pattern, src ast.Expr) {
+	t := pass.TypesInfo.TypeOf(src)
+	if t == nil || types.IsInterface(t) {
+		return
+	}
+	if ptr, ok := t.Underlying().(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if _, ok := t.Underlying().(*types.Struct); !ok {
+		pass.Reportf(src.Pos(), "Build needs a structure or a pointer to one, not %v", typeString(pass, pass.TypesInfo.TypeOf(src)))
+		return
+	}
+	checkFields(pass, "Build", p, src, t, typesx.Formattable)
+}
+
+// checkFields reports the placeholders of p that have no field in the structure type t,
+// and those whose field has a type that is not supported by name.
+func checkFields(pass *analysis.Pass, name string, p *mapper.Pattern, arg ast.Expr, t types.Type, supported func(types.Type) bool) {
+	fields := typesx.Fields(t)
+	for _, placeholder := range p.Names() {
+		if placeholder == "" {
+			// Mapping ignores an unnamed catch-all, which Build cannot fill.
+			if name == "Build" {
+				pass.Reportf(arg.Pos(), "Build cannot fill the unnamed catch-all of pattern %q", p)
+			}
+			continue
+		}
+		f, ok := fields[placeholder]
+		if !ok {
+			pass.Reportf(arg.Pos(), "placeholder %v of pattern %q has no field in %v", placeholder, p, typeString(pass, t))
+			continue
+		}
+		if field := f[len(f)-1]; !supported(field.Type()) {
+			pass.Reportf(arg.Pos(), "%v does not support field %v of placeholder %v, which has type %v", name, field.Name(), placeholder, typeString(pass, field.Type()))
+		}
+	}
+}
+
+// typeString writes t as the code of the analyzed package refers to it.
+func typeString(pass *analysis.Pass, t types.Type) string {
+	return types.TypeString(t, types.RelativeTo(pass.Pkg))
+}
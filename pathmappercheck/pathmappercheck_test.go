@@ -0,0 +1,12 @@
+package pathmappercheck_test
+
+import (
+	"testing"
+
+	"github.com/KamikazeZirou/path-mapper/pathmappercheck"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), pathmappercheck.Analyzer, "a")
+}
@@ -0,0 +1,66 @@
+package a
+
+import (
+	mapper "github.com/KamikazeZirou/path-mapper"
+)
+
+type Issue struct {
+	Owner  string
+	Number int
+	Slug   Slug
+	Ratio  float64
+	Repo   string `alias:"repository"`
+}
+
+type Slug struct{ text string }
+
+func (s *Slug) Parse(text string) (interface{}, error) { return Slug{text}, nil }
+
+func (s Slug) MarshalText() ([]byte, error) { return []byte(s.text), nil }
+
+var issues = mapper.MustCompile("/{owner}/{repository}/issues/{number:int}")
+
+var reassigned = mapper.MustCompile("/{owner}/{missing}")
+
+func init() {
+	reassigned = mapper.MustCompile("/{owner}")
+}
+
+func mapping(path string, dest interface{}) {
+	var issue Issue
+	mapper.Mapping("/{owner}/{number}/{slug}", path, &issue)
+	mapper.Mapping("/{owner}/{number", path, &issue) // want `invalid pattern: .*`
+	mapper.Mapping("/{owner}/{title}", path, &issue) // want `placeholder title of pattern "/{owner}/{title}" has no field in Issue`
+	mapper.Mapping("/{owner}/{ratio}", path, &issue) // want `Mapping does not support field Ratio of placeholder ratio, which has type float64`
+	mapper.Mapping("/{owner}", path, issue)          // want `Mapping needs a pointer to a structure, not Issue`
+	mapper.Mapping("/{owner}", path, new(string))    // want `Mapping needs a pointer to a structure, not \*string`
+	mapper.Mapping("/{owner}/{title}", path, dest)
+	mapper.Mapping("/{owner}/{title}", path, &issue, mapper.WithoutDecoding())
+	mapper.MatchPrefix("/{owner}/{title}", path, &issue) // want `placeholder title .*`
+	mapper.Mapping("/{owner}/{...}", path, &issue)
+
+	issues.Mapping(path, &issue)
+	issues.MatchPrefix(path, issue) // want `MatchPrefix needs a pointer to a structure, not Issue`
+	reassigned.Mapping(path, &issue)
+	mapper.MustCompile("/{title}").Mapping(path, &issue) // want `placeholder title .*`
+
+	local, err := mapper.Compile("/{owner}/{ratio}")
+	if err != nil {
+		return
+	}
+	local.Mapping(path, &issue) // want `Mapping does not support field Ratio .*`
+
+	aliased := mapper.MustCompile("/{title}", mapper.WithoutDecoding())
+	aliased.Mapping(path, &issue)
+
+	mapper.MustCompile("/{owner") // want `invalid pattern: .*`
+}
+
+func build(issue Issue) {
+	mapper.Build("/{owner}/{number}/{slug}", issue)
+	mapper.Build("/{owner}/{ratio}", &issue) // want `Build does not support field Ratio of placeholder ratio, which has type float64`
+	mapper.Build("/{owner}/{...}", issue)    // want `Build cannot fill the unnamed catch-all of pattern "/{owner}/{...}"`
+	mapper.Build("/{owner}", "owner")        // want `Build needs a structure or a pointer to one, not string`
+	issues.Build(issue)
+	issues.Build(&struct{ Owner string }{}) // want `placeholder repository .*` `placeholder number .*`
+}
@@ -0,0 +1,26 @@
+// Package path_mapper is a stub of the functions that the analyzer checks.
+package path_mapper
+
+type Option func()
+
+type Pattern struct{}
+
+func Compile(pattern string, opts ...Option) (*Pattern, error) { return &Pattern{}, nil }
+
+func MustCompile(pattern string, opts ...Option) *Pattern { return &Pattern{} }
+
+func Mapping(pattern, path string, dest interface{}, opts ...Option) error { return nil }
+
+func MatchPrefix(pattern, path string, dest interface{}, opts ...Option) (string, error) {
+	return "", nil
+}
+
+func Build(pattern string, src interface{}, opts ...Option) (string, error) { return "", nil }
+
+func WithoutDecoding() Option { return nil }
+
+func (p *Pattern) Mapping(path string, dest interface{}) error { return nil }
+
+func (p *Pattern) MatchPrefix(path string, dest interface{}) (string, error) { return "", nil }
+
+func (p *Pattern) Build(src interface{}) (string, error) { return "", nil }
@@ -0,0 +1,490 @@
+package path_mapper
+
+import (
+	"fmt"
+	"strings"
+)
+
+type segmentKind int
+
+// The kinds of segments are ordered from the most specific to the least specific.
+const (
+	literalSegment segmentKind = iota
+	mixedSegment
+	placeholderSegment
+	catchAllSegment
+)
+
+type segment struct {
+	kind segmentKind
+	// value is the literal text of a literal segment, the name of a placeholder,
+	// or the source text of a mixed segment.
+	value string
+	// sep is the separator that precedes the segment, or 0 for the first segment.
+	sep byte
+	// parts holds the literals and placeholders of a mixed segment.
+	parts []segment
+	// constraint restricts the values of a placeholder. It is nil if the placeholder accepts any value.
+	constraint *constraint
+}
+
+// Pattern is a compiled path pattern such as "/{owner}/{repository}/issues/{number}".
+//
+// Patterns and paths are split into segments at "/", or at the separators given by WithSeparators.
+// A segment enclosed in braces is a placeholder that captures exactly one path segment.
+// Placeholders can also be mixed with literal text within a segment, as in "{day}.log" or "{name}.{ext}",
+// as long as two placeholders are separated by some text. Each placeholder of such a segment
+// captures the shortest text that lets the rest of the segment match, except the last one,
+// which captures everything up to its literal suffix: "a.tar.gz" maps "a" into {name} and "tar.gz" into {ext}.
+// A placeholder whose name ends with "..." is a catch-all; it must be the last segment
+// and captures the rest of the path, separators included.
+// The name of a catch-all may be omitted, as in "{...}", when its value is not needed.
+//
+// A placeholder can be followed by a constraint that the whole decoded value must satisfy,
+// as in "{id:int}" or "{kind:issues|pulls}": one of int, uint, float and uuid, or else a regular expression.
+// Braces in a constraint must be balanced. Catch-alls cannot have a constraint.
+//
+// A path is split before it is percent-decoded, so an escaped slash ("%2F") never separates segments.
+// Each segment is then decoded: literal segments of the pattern are compared with the decoded segment,
+// and placeholders capture the decoded value. A catch-all captures the decoded rest of the path,
+// where an escaped slash can no longer be told apart from a separator.
+// A path containing an invalid escape does not match. WithoutDecoding turns decoding off.
+//
+// Paths are matched as they are unless WithNormalization cleans them up first,
+// and literal segments are case-sensitive unless WithLiteralCase says otherwise.
+type Pattern struct {
+	raw      string
+	segments []segment
+	opts     options
+}
+
+// Compile parses a pattern.
+func Compile(pattern string, opts ...Option) (*Pattern, error) {
+	return compile(pattern, newOptions(opts))
+}
+
+func compile(pattern string, o options) (*Pattern, error) {
+	if err := o.validateSeparators(); err != nil {
+		return nil, fmt.Errorf("pattern(%v): %w", pattern, err)
+	}
+
+	text, err := TranslatePattern(pattern, o.dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	parts, seps := splitSegments(text, o.separators(), true)
+	segments := make([]segment, 0, len(parts))
+	for i, part := range parts {
+		s, err := parseSegment(part)
+		if err != nil {
+			return nil, fmt.Errorf("pattern(%v): %w", pattern, err)
+		}
+		if s.kind == catchAllSegment && i != len(parts)-1 {
+			return nil, fmt.Errorf("pattern(%v): catch-all %v must be the last segment", pattern, part)
+		}
+		s.sep = seps[i]
+		segments = append(segments, s)
+	}
+
+	// Like NormalizePath, remove the trailing slashes of any pattern but the root pattern "/".
+	for o.normalization&IgnoreTrailingSlash != 0 {
+		last := len(segments) - 1
+		if last < 1 || segments[last].kind != literalSegment || segments[last].value != "" || segments[last].sep != '/' {
+			break
+		}
+		if last == 1 && segments[0].kind == literalSegment && segments[0].value == "" {
+			break
+		}
+		segments = segments[:last]
+	}
+	return &Pattern{raw: pattern, segments: segments, opts: o}, nil
+}
+
+// MustCompile is like Compile but panics if the pattern cannot be parsed.
+func MustCompile(pattern string, opts ...Option) *Pattern {
+	p, err := Compile(pattern, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// Join returns the pattern that matches the paths of p followed by the paths of pattern,
+// compiled with the options of p. pattern must be empty or start with a separator,
+// which is shared with a trailing separator of p.
+// It fails if p ends in a catch-all or if both parts use the same placeholder name,
+// so the joined pattern can be mapped into a structure that embeds the structure of each part.
+func (p *Pattern) Join(pattern string) (*Pattern, error) {
+	seps := p.opts.separators()
+	if pattern != "" && strings.IndexByte(seps, pattern[0]) < 0 {
+		return nil, fmt.Errorf("pattern(%v) joined to pattern(%v) must start with a separator", pattern, p.raw)
+	}
+	if n := len(p.segments); n > 0 && p.segments[n-1].kind == catchAllSegment {
+		return nil, fmt.Errorf("pattern(%v) cannot be joined because it ends in a catch-all", p.raw)
+	}
+
+	other, err := compile(pattern, p.opts)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool)
+	for _, s := range p.segments {
+		for _, name := range s.placeholders() {
+			names[name] = true
+		}
+	}
+	for _, s := range other.segments {
+		for _, name := range s.placeholders() {
+			if name != "" && names[name] {
+				return nil, fmt.Errorf("pattern(%v) and pattern(%v) both use placeholder %v", p.raw, pattern, name)
+			}
+		}
+	}
+
+	base := p.raw
+	if pattern != "" && base != "" && base[len(base)-1] == pattern[0] {
+		base = base[:len(base)-1]
+	}
+	return compile(base+pattern, p.opts)
+}
+
+// MustJoin is like Join but panics if the patterns cannot be joined.
+func (p *Pattern) MustJoin(pattern string) *Pattern {
+	joined, err := p.Join(pattern)
+	if err != nil {
+		panic(err)
+	}
+	return joined
+}
+
+// String returns the source text used to compile the pattern.
+func (p *Pattern) String() string {
+	return p.raw
+}
+
+// Mapping maps path into dest as the package-level Mapping does.
+func (p *Pattern) Mapping(path string, dest interface{}) error {
+	names, values, ok := p.match(path)
+	if !ok {
+		return &MismatchError{Pattern: p.raw, Path: path}
+	}
+	return bind(names, values, dest)
+}
+
+// Names returns the names of the placeholders of the pattern in the order in which they appear.
+// The name of an unnamed catch-all is empty.
+func (p *Pattern) Names() []string {
+	var names []string
+	for _, s := range p.segments {
+		names = append(names, s.placeholders()...)
+	}
+	return names
+}
+
+// Equivalent reports whether p and q match the same paths with the same placeholders, whatever their names,
+// so that a PatternSet keeps only the first of them.
+func (p *Pattern) Equivalent(q *Pattern) bool {
+	return sameShape(p, q, &p.opts)
+}
+
+// Constraints returns the constraints of the placeholders of the pattern as they are written, such as "int",
+// in the order of Names. The constraint of a placeholder that accepts any value is empty.
+func (p *Pattern) Constraints() []string {
+	var constraints []string
+	for _, s := range p.segments {
+		segments := []segment{s}
+		if s.kind == mixedSegment {
+			segments = s.parts
+		}
+		for _, s := range segments {
+			if s.kind != placeholderSegment && s.kind != catchAllSegment {
+				continue
+			}
+			if s.constraint != nil {
+				constraints = append(constraints, s.constraint.text)
+			} else {
+				constraints = append(constraints, "")
+			}
+		}
+	}
+	return constraints
+}
+
+// Values returns the values that the placeholders of the pattern capture from path, in the order in which they appear,
+// or false if path does not match. Code generated by pathmapper-gen maps paths with it instead of Mapping.
+func (p *Pattern) Values(path string) ([]string, bool) {
+	_, values, ok := p.match(path)
+	return values, ok
+}
+
+// MatchPrefix maps the leading segments of path that match the pattern into dest,
+// and returns the rest of the path, which starts with a separator unless it is empty.
+// The rest is neither decoded nor normalized again, so it can be passed to another pattern.
+// A pattern that ends in a catch-all always leaves an empty rest.
+// A pattern that ends in a separator, such as "/api/{version}/", is mounted at that separator:
+// it matches a path only if the separator follows the prefix, and the rest starts with it.
+func (p *Pattern) MatchPrefix(path string, dest interface{}) (rest string, err error) {
+	prefix := NormalizePath(path, p.opts.normalization)
+	n := len(p.segments)
+	trailing := n > 1 && p.segments[n-1].kind == literalSegment && p.segments[n-1].value == ""
+	if trailing {
+		n--
+	}
+	if n > 0 && p.segments[n-1].kind != catchAllSegment {
+		// The prefix made of n segments ends before the n-th separator.
+		seps := p.opts.separators()
+		for i := 0; i < len(prefix); i++ {
+			if strings.IndexByte(seps, prefix[i]) < 0 {
+				continue
+			}
+			if n--; n == 0 {
+				prefix, rest = prefix[:i], prefix[i:]
+				break
+			}
+		}
+	}
+	if trailing {
+		if rest == "" {
+			return "", &MismatchError{Pattern: p.raw, Path: path}
+		}
+		prefix += rest[:1]
+	}
+
+	names, values, ok := p.matchNormalized(prefix)
+	if !ok {
+		return "", &MismatchError{Pattern: p.raw, Path: path}
+	}
+	return rest, bind(names, values, dest)
+}
+
+// match reports whether path matches the pattern and returns the captured placeholder names and values.
+func (p *Pattern) match(path string) (names, values []string, ok bool) {
+	return p.matchNormalized(NormalizePath(path, p.opts.normalization))
+}
+
+func (p *Pattern) matchNormalized(path string) (names, values []string, ok bool) {
+	pathSegments, seps := splitSegments(path, p.opts.separators(), false)
+	n := len(p.segments)
+	if n > 0 && p.segments[n-1].kind == catchAllSegment {
+		if len(pathSegments) < n {
+			return nil, nil, false
+		}
+	} else if len(pathSegments) != n {
+		return nil, nil, false
+	}
+
+	names = make([]string, 0, n)
+	values = make([]string, 0, n)
+	offset := 0
+	for i, s := range p.segments {
+		if seps[i] != s.sep {
+			return nil, nil, false
+		}
+		value := pathSegments[i]
+		if s.kind == catchAllSegment {
+			value = path[offset:]
+		}
+		// Every separator is a single byte.
+		offset += len(pathSegments[i]) + 1
+
+		value, ok = p.opts.decode(value)
+		if !ok {
+			return nil, nil, false
+		}
+
+		switch s.kind {
+		case literalSegment:
+			if !p.opts.equalLiteral(s.value, value) {
+				return nil, nil, false
+			}
+		case mixedSegment:
+			ok = s.matchMixed(value, &p.opts, func(v string) {
+				values = append(values, v)
+			})
+			if !ok {
+				return nil, nil, false
+			}
+			names = append(names, s.placeholders()...)
+		case placeholderSegment, catchAllSegment:
+			if !s.allows(value) {
+				return nil, nil, false
+			}
+			names = append(names, s.value)
+			values = append(values, value)
+		}
+	}
+	return names, values, true
+}
+
+// parseSegment parses a segment of a pattern.
+func parseSegment(text string) (segment, error) {
+	if !strings.ContainsAny(text, "{}") {
+		return segment{kind: literalSegment, value: text}, nil
+	}
+
+	if strings.HasPrefix(text, "{") && closingBrace(text, 0) == len(text)-1 {
+		return parsePlaceholder(text[1 : len(text)-1])
+	}
+
+	s := segment{kind: mixedSegment, value: text}
+	for rest := text; rest != ""; {
+		i := strings.IndexAny(rest, "{}")
+		if i < 0 {
+			s.parts = append(s.parts, segment{kind: literalSegment, value: rest})
+			break
+		}
+		if rest[i] == '}' {
+			return segment{}, fmt.Errorf("unexpected } in %v", text)
+		}
+		if i > 0 {
+			s.parts = append(s.parts, segment{kind: literalSegment, value: rest[:i]})
+		}
+
+		j := closingBrace(rest, i)
+		if j < 0 {
+			return segment{}, fmt.Errorf("unclosed { in %v", text)
+		}
+		part, err := parsePlaceholder(rest[i+1 : j])
+		if err != nil {
+			return segment{}, err
+		}
+		if part.kind == catchAllSegment {
+			return segment{}, fmt.Errorf("placeholder in %v cannot be a catch-all", text)
+		}
+		if n := len(s.parts); n > 0 && s.parts[n-1].kind == placeholderSegment {
+			return segment{}, fmt.Errorf("placeholders in %v must be separated by some text", text)
+		}
+		s.parts = append(s.parts, part)
+		rest = rest[j+1:]
+	}
+	return s, nil
+}
+
+// closingBrace returns the index of the brace that closes the one at s[open], or -1.
+func closingBrace(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// placeholders returns the names of the placeholders in the segment.
+func (s segment) placeholders() []string {
+	switch s.kind {
+	case placeholderSegment, catchAllSegment:
+		return []string{s.value}
+	case mixedSegment:
+		var names []string
+		for _, part := range s.parts {
+			if part.kind == placeholderSegment {
+				names = append(names, part.value)
+			}
+		}
+		return names
+	}
+	return nil
+}
+
+// matchMixed matches value against the parts of a mixed segment,
+// calling capture with the value of each placeholder.
+func (s *segment) matchMixed(value string, o *options, capture func(string)) bool {
+	for i, part := range s.parts {
+		if part.kind == literalSegment {
+			n := o.literalPrefix(value, part.value)
+			if n < 0 {
+				return false
+			}
+			value = value[n:]
+			continue
+		}
+
+		j := len(value)
+		if i+1 == len(s.parts)-1 {
+			// The last placeholder before a suffix captures everything up to the suffix.
+			n := o.literalSuffix(value, s.parts[i+1].value)
+			if n < 0 {
+				return false
+			}
+			j -= n
+		} else if i+1 < len(s.parts) {
+			j = o.indexLiteral(value, s.parts[i+1].value)
+		}
+		if j < 0 || !part.allows(value[:j]) {
+			return false
+		}
+		capture(value[:j])
+		value = value[j:]
+	}
+	return value == ""
+}
+
+// mixedKey returns the form in which mixed segments that match the same values are identical.
+func (s *segment) mixedKey(o *options) string {
+	var b strings.Builder
+	for _, part := range s.parts {
+		if part.kind == literalSegment {
+			b.WriteString(o.literalKey(part.value))
+		} else {
+			b.WriteString("{" + part.constraintKey() + "}")
+		}
+	}
+	return b.String()
+}
+
+// splitSegments splits s at every separator in seps, and returns the segments
+// together with the separator that precedes each of them, which is 0 for the first one.
+// If braces is true, separators inside braces are part of a placeholder and do not split s.
+func splitSegments(s, seps string, braces bool) (segments []string, separators []byte) {
+	separators = append(separators, 0)
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case braces && s[i] == '{':
+			depth++
+		case braces && s[i] == '}':
+			depth--
+		case depth == 0 && strings.IndexByte(seps, s[i]) >= 0:
+			segments = append(segments, s[start:i])
+			separators = append(separators, s[i])
+			start = i + 1
+		}
+	}
+	return append(segments, s[start:]), separators
+}
+
+func (k segmentKind) String() string {
+	switch k {
+	case literalSegment:
+		return "literal"
+	case mixedSegment:
+		return "mixed segment"
+	case placeholderSegment:
+		return "placeholder"
+	case catchAllSegment:
+		return "catch-all"
+	}
+	return "unknown"
+}
+
+// String returns the segment as it is written in a pattern.
+func (s segment) String() string {
+	switch s.kind {
+	case placeholderSegment:
+		if s.constraint != nil {
+			return "{" + s.value + ":" + s.constraint.text + "}"
+		}
+		return "{" + s.value + "}"
+	case catchAllSegment:
+		return "{" + s.value + "...}"
+	}
+	return s.value
+}
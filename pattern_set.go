@@ -0,0 +1,284 @@
+package path_mapper
+
+// Param is a placeholder name and the path value it captured.
+type Param struct {
+	Name  string
+	Value string
+}
+
+// Match is the result of matching a path against a PatternSet.
+type Match struct {
+	// Pattern is the pattern that matched.
+	Pattern *Pattern
+	// Value is the value registered together with Pattern, such as a handler or a destination type.
+	Value interface{}
+	// Params holds the captured placeholders in pattern order.
+	Params []Param
+	// Path is the canonical form of the matched path under the normalization of the PatternSet.
+	// When it differs from the path passed to Match, callers may redirect to it.
+	Path string
+}
+
+// Mapping maps the captured placeholders into dest.
+func (m *Match) Mapping(dest interface{}) error {
+	names := make([]string, len(m.Params))
+	values := make([]string, len(m.Params))
+	for i, param := range m.Params {
+		names[i] = param.Name
+		values[i] = param.Value
+	}
+	return bind(names, values, dest)
+}
+
+type patternEntry struct {
+	pattern *Pattern
+	value   interface{}
+	// names holds the placeholder names of pattern in order.
+	names []string
+}
+
+// node is a node of the segment tree used by PatternSet.
+// The literal, placeholder and catch-all children of a node match the next segment,
+// and the node reached by a segment has an edge for each separator that may follow it.
+type node struct {
+	literals map[string]*node
+	mixed    []mixedEdge
+	// params holds a child for each constraint of the placeholders that match the next segment.
+	// Constrained placeholders come first, and the placeholder without a constraint last.
+	params []paramEdge
+	// catchAll is the pattern whose catch-all captures the rest of the path from this node.
+	catchAll *patternEntry
+	// entry is the pattern that ends at this node.
+	entry *patternEntry
+	seps  []sepEdge
+}
+
+// mixedEdge is a child for a mixed segment; mixed segments are tried in the order they were added.
+type mixedEdge struct {
+	segment *segment
+	key     string
+	next    *node
+}
+
+type paramEdge struct {
+	segment *segment
+	next    *node
+}
+
+type sepEdge struct {
+	sep  byte
+	next *node
+}
+
+func (n *node) sepChild(sep byte) *node {
+	for _, e := range n.seps {
+		if e.sep == sep {
+			return e.next
+		}
+	}
+	return nil
+}
+
+func (n *node) mixedChild(s *segment, o *options) *node {
+	key := s.mixedKey(o)
+	for _, e := range n.mixed {
+		if e.key == key {
+			return e.next
+		}
+	}
+	child := &node{}
+	n.mixed = append(n.mixed, mixedEdge{segment: s, key: key, next: child})
+	return child
+}
+
+func (n *node) paramChild(s *segment) *node {
+	key := s.constraintKey()
+	for _, e := range n.params {
+		if e.segment.constraintKey() == key {
+			return e.next
+		}
+	}
+	child := &node{}
+	edge := paramEdge{segment: s, next: child}
+	if i := len(n.params) - 1; s.constraint != nil && i >= 0 && n.params[i].segment.constraint == nil {
+		n.params = append(n.params[:i], edge, n.params[i])
+	} else {
+		n.params = append(n.params, edge)
+	}
+	return child
+}
+
+func (n *node) insert(e *patternEntry, o *options) {
+	for i, s := range e.pattern.segments {
+		if i > 0 {
+			child := n.sepChild(s.sep)
+			if child == nil {
+				child = &node{}
+				n.seps = append(n.seps, sepEdge{sep: s.sep, next: child})
+			}
+			n = child
+		}
+
+		switch s.kind {
+		case literalSegment:
+			if n.literals == nil {
+				n.literals = make(map[string]*node)
+			}
+			key := o.literalKey(s.value)
+			child, ok := n.literals[key]
+			if !ok {
+				child = &node{}
+				n.literals[key] = child
+			}
+			n = child
+		case mixedSegment:
+			n = n.mixedChild(&e.pattern.segments[i], o)
+		case placeholderSegment:
+			n = n.paramChild(&e.pattern.segments[i])
+		case catchAllSegment:
+			if n.catchAll == nil {
+				n.catchAll = e
+			}
+			return
+		}
+	}
+	if n.entry == nil {
+		n.entry = e
+	}
+}
+
+// lookup matches the segments of path against the children of n.
+// Literals are tried before mixed segments, mixed segments before placeholders,
+// and placeholders before catch-alls,
+// backtracking when a branch does not lead to a pattern.
+// The captured values are appended to m.Params.
+func (n *node) lookup(path string, o *options, m *Match) *patternEntry {
+	seg, rest, sep := path, "", byte(0)
+	if i := o.indexSeparator(path); i >= 0 {
+		seg, rest, sep = path[:i], path[i+1:], path[i]
+	}
+	seg, ok := o.decode(seg)
+	if !ok {
+		return nil
+	}
+
+	if child, ok := n.literals[o.literalKey(seg)]; ok {
+		if e := child.next(rest, sep, o, m); e != nil {
+			return e
+		}
+	}
+	for _, edge := range n.mixed {
+		mark := len(m.Params)
+		ok := edge.segment.matchMixed(seg, o, func(v string) {
+			m.Params = append(m.Params, Param{Value: v})
+		})
+		if ok {
+			if e := edge.next.next(rest, sep, o, m); e != nil {
+				return e
+			}
+		}
+		m.Params = m.Params[:mark]
+	}
+	for _, edge := range n.params {
+		if !edge.segment.allows(seg) {
+			continue
+		}
+		m.Params = append(m.Params, Param{Value: seg})
+		if e := edge.next.next(rest, sep, o, m); e != nil {
+			return e
+		}
+		m.Params = m.Params[:len(m.Params)-1]
+	}
+	if n.catchAll != nil {
+		if value, ok := o.decode(path); ok {
+			m.Params = append(m.Params, Param{Value: value})
+			return n.catchAll
+		}
+	}
+	return nil
+}
+
+// next continues the lookup after a segment that ended with sep, or at the end of the path if sep is 0.
+func (n *node) next(rest string, sep byte, o *options, m *Match) *patternEntry {
+	if sep == 0 {
+		return n.entry
+	}
+	child := n.sepChild(sep)
+	if child == nil {
+		return nil
+	}
+	return child.lookup(rest, o, m)
+}
+
+// PatternSet matches a path against many patterns at once and reports which one matched.
+//
+// When several patterns match the same path, the most specific one wins.
+// Segments are compared from left to right, and at the first segment where they differ
+// a literal beats a mixed segment such as "{day}.log", which beats a placeholder, which beats a catch-all,
+// and a placeholder with a constraint beats one without.
+// Of patterns that are equally specific, the one added first wins.
+//
+// The patterns are stored in a tree of segments,
+// so the cost of a lookup depends on the length of the path rather than on the number of patterns.
+type PatternSet struct {
+	root    node
+	entries []*patternEntry
+	opts    options
+}
+
+// NewPatternSet returns an empty PatternSet.
+// opts configure how paths are matched, and apply to every pattern in the set.
+func NewPatternSet(opts ...Option) *PatternSet {
+	return &PatternSet{opts: newOptions(opts)}
+}
+
+// Add compiles pattern with the options of the set and registers it with value.
+// value is returned as is in Match.Value; typically it is a handler or a destination type.
+func (s *PatternSet) Add(pattern string, value interface{}) error {
+	p, err := compile(pattern, s.opts)
+	if err != nil {
+		return err
+	}
+	s.AddPattern(p, value)
+	return nil
+}
+
+// AddPattern registers a compiled pattern with value.
+// The options of the set, rather than those p was compiled with, decide how paths are matched,
+// so p should be compiled with the same options.
+func (s *PatternSet) AddPattern(p *Pattern, value interface{}) {
+	e := &patternEntry{pattern: p, value: value}
+	for _, seg := range p.segments {
+		e.names = append(e.names, seg.placeholders()...)
+	}
+	s.entries = append(s.entries, e)
+	s.root.insert(e, &s.opts)
+}
+
+// Match returns the most specific pattern that matches path.
+func (s *PatternSet) Match(path string) (*Match, bool) {
+	m := &Match{}
+	if !s.MatchInto(path, m) {
+		return nil, false
+	}
+	return m, true
+}
+
+// MatchInto is like Match but stores the result in m, reusing the capacity of m.Params.
+// Reusing a Match makes a successful lookup free of allocations.
+// m is left in an unspecified state if path does not match.
+func (s *PatternSet) MatchInto(path string, m *Match) bool {
+	m.Params = m.Params[:0]
+	m.Path = NormalizePath(path, s.opts.normalization)
+	e := s.root.lookup(m.Path, &s.opts, m)
+	if e == nil {
+		return false
+	}
+
+	m.Pattern = e.pattern
+	m.Value = e.value
+	for i, name := range e.names {
+		m.Params[i].Name = name
+	}
+	return true
+}
@@ -0,0 +1,357 @@
+package path_mapper
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPatternSet_Match(t *testing.T) {
+	s := NewPatternSet()
+	for _, pattern := range []string{
+		"/{owner}/{repository}/{kind}/{number}",
+		"/{owner}/{repository}/issues/{number}",
+		"/{owner}/{repository}/pulls/{number}",
+		"/{owner}/{repository}/actions/runs/{id}",
+		"/{owner}/{repository}/{path...}",
+		"/KamikazeZirou/{repository}/{path...}",
+	} {
+		if err := s.Add(pattern, pattern); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		params  []Param
+		success bool
+	}{
+		{
+			name: "Literal beats placeholder",
+			path: "/guest/sandbox/issues/1",
+			want: "/{owner}/{repository}/issues/{number}",
+			params: []Param{
+				{Name: "owner", Value: "guest"},
+				{Name: "repository", Value: "sandbox"},
+				{Name: "number", Value: "1"},
+			},
+			success: true,
+		},
+		{
+			name: "Placeholder beats catch-all",
+			path: "/guest/sandbox/discussions/1",
+			want: "/{owner}/{repository}/{kind}/{number}",
+			params: []Param{
+				{Name: "owner", Value: "guest"},
+				{Name: "repository", Value: "sandbox"},
+				{Name: "kind", Value: "discussions"},
+				{Name: "number", Value: "1"},
+			},
+			success: true,
+		},
+		{
+			name: "Catch-all",
+			path: "/guest/sandbox/actions/runs/1/jobs",
+			want: "/{owner}/{repository}/{path...}",
+			params: []Param{
+				{Name: "owner", Value: "guest"},
+				{Name: "repository", Value: "sandbox"},
+				{Name: "path", Value: "actions/runs/1/jobs"},
+			},
+			success: true,
+		},
+		{
+			name: "The leftmost literal decides",
+			path: "/KamikazeZirou/path-mapper/issues/1",
+			want: "/KamikazeZirou/{repository}/{path...}",
+			params: []Param{
+				{Name: "repository", Value: "path-mapper"},
+				{Name: "path", Value: "issues/1"},
+			},
+			success: true,
+		},
+		{
+			name:    "No pattern matches",
+			path:    "/guest",
+			success: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, ok := s.Match(tt.path)
+			if ok != tt.success {
+				t.Fatalf("Match() return %v, which is not what we want.", ok)
+			}
+			if !ok {
+				return
+			}
+			if m.Value != tt.want {
+				t.Errorf("Match() matched %v, want %v", m.Value, tt.want)
+			}
+			if diff := cmp.Diff(tt.params, m.Params); diff != "" {
+				t.Errorf("Match() params mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMatch_Mapping(t *testing.T) {
+	s := NewPatternSet()
+	_ = s.Add("/{owner}/{repository}/issues/{number}", nil)
+
+	m, ok := s.Match("/KamikazeZirou/path-mapper/issues/1")
+	if !ok {
+		t.Fatal("Match() does not match")
+	}
+
+	got := &GitHubIssue{}
+	if err := m.Mapping(got); err != nil {
+		t.Fatal(err)
+	}
+	want := &GitHubIssue{Owner: "KamikazeZirou", Repository: "path-mapper", Number: 1}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Mapping() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPatternSet_Match_Mixed(t *testing.T) {
+	s := NewPatternSet()
+	for _, pattern := range []string{
+		"/files/{path...}",
+		"/files/{name}",
+		"/files/{name}.{ext}",
+		"/files/{name}.txt",
+		"/files/README.txt",
+	} {
+		if err := s.Add(pattern, pattern); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		path   string
+		want   string
+		params []Param
+	}{
+		{
+			path: "/files/README.txt",
+			want: "/files/README.txt",
+		},
+		{
+			path:   "/files/notes.txt",
+			want:   "/files/{name}.{ext}",
+			params: []Param{{Name: "name", Value: "notes"}, {Name: "ext", Value: "txt"}},
+		},
+		{
+			path:   "/files/notes",
+			want:   "/files/{name}",
+			params: []Param{{Name: "name", Value: "notes"}},
+		},
+		{
+			path:   "/files/docs/notes.txt",
+			want:   "/files/{path...}",
+			params: []Param{{Name: "path", Value: "docs/notes.txt"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			m, ok := s.Match(tt.path)
+			if !ok {
+				t.Fatal("Match() does not match")
+			}
+			if got := m.Pattern.String(); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+			if diff := cmp.Diff(tt.params, m.Params); diff != "" {
+				t.Errorf("Match() params mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPatternSet_Match_Constraint(t *testing.T) {
+	s := NewPatternSet()
+	for _, pattern := range []string{
+		"/users/{name}",
+		"/users/{id:int}",
+		"/users/{id:uuid}/keys",
+		"/users/{id:uuid}",
+	} {
+		if err := s.Add(pattern, pattern); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "/users/42", want: "/users/{id:int}"},
+		{path: "/users/guest", want: "/users/{name}"},
+		{path: "/users/0a1b2c3d-0000-0000-0000-000000000000", want: "/users/{id:uuid}"},
+		{path: "/users/0a1b2c3d-0000-0000-0000-000000000000/keys", want: "/users/{id:uuid}/keys"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			m, ok := s.Match(tt.path)
+			if !ok {
+				t.Fatal("Match() does not match")
+			}
+			if got := m.Pattern.String(); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPatternSet_MatchInto(t *testing.T) {
+	s := NewPatternSet()
+	_ = s.Add("/{owner}/{repository}/issues/{number}", nil)
+	_ = s.Add("/{owner}/{repository}/{path...}", nil)
+
+	m := &Match{}
+	allocs := testing.AllocsPerRun(100, func() {
+		if !s.MatchInto("/KamikazeZirou/path-mapper/issues/1", m) {
+			t.Fatal("MatchInto() does not match")
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("MatchInto() allocates %v times, want 0", allocs)
+	}
+}
+
+func routes(n int) []string {
+	patterns := make([]string, 0, n)
+	for i := 0; len(patterns) < n; i++ {
+		patterns = append(patterns,
+			fmt.Sprintf("/service%d/{owner}/{repository}/issues/{number}", i),
+			fmt.Sprintf("/service%d/{owner}/{repository}/pulls/{number}", i),
+			fmt.Sprintf("/service%d/{owner}/{repository}/actions/runs/{id}", i),
+			fmt.Sprintf("/service%d/{owner}/{repository}/{path...}", i),
+		)
+	}
+	return patterns[:n]
+}
+
+const benchmarkPath = "/service499/KamikazeZirou/path-mapper/issues/1"
+
+func BenchmarkPatternSet_Match(b *testing.B) {
+	s := NewPatternSet()
+	for _, pattern := range routes(2000) {
+		_ = s.Add(pattern, nil)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, ok := s.Match(benchmarkPath); !ok {
+			b.Fatal("Match() does not match")
+		}
+	}
+}
+
+func BenchmarkPatternSet_MatchInto(b *testing.B) {
+	s := NewPatternSet()
+	for _, pattern := range routes(2000) {
+		_ = s.Add(pattern, nil)
+	}
+	m := &Match{}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if !s.MatchInto(benchmarkPath, m) {
+			b.Fatal("MatchInto() does not match")
+		}
+	}
+}
+
+func BenchmarkMapping_Loop(b *testing.B) {
+	patterns := routes(2000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		st := GitHubIssue{}
+		for _, pattern := range patterns {
+			if Mapping(pattern, benchmarkPath, &st) == nil {
+				break
+			}
+		}
+	}
+}
+
+func BenchmarkPatternSet_MatchAndMapping(b *testing.B) {
+	s := NewPatternSet()
+	for _, pattern := range routes(2000) {
+		_ = s.Add(pattern, nil)
+	}
+	m := &Match{}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		st := GitHubIssue{}
+		if !s.MatchInto(benchmarkPath, m) {
+			b.Fatal("MatchInto() does not match")
+		}
+		if err := m.Mapping(&st); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestPatternSet_Decoding(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		path    string
+		params  []Param
+		success bool
+	}{
+		{
+			name: "Decoded",
+			path: "/John%20Doe/my%20files/docs%2FREADME.md",
+			params: []Param{
+				{Name: "owner", Value: "John Doe"},
+				{Name: "path", Value: "docs/README.md"},
+			},
+			success: true,
+		},
+		{
+			name:    "Invalid escape",
+			path:    "/guest/my%20files/100%",
+			success: false,
+		},
+		{
+			name:    "Literal is not decoded without decoding",
+			opts:    []Option{WithoutDecoding()},
+			path:    "/John%20Doe/my%20files/docs%2FREADME.md",
+			success: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewPatternSet(tt.opts...)
+			_ = s.Add("/{owner}/my files/{path}", nil)
+
+			m, ok := s.Match(tt.path)
+			if ok != tt.success {
+				t.Fatalf("Match() return %v, which is not what we want.", ok)
+			}
+			if !ok {
+				return
+			}
+			if diff := cmp.Diff(tt.params, m.Params); diff != "" {
+				t.Errorf("Match() params mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
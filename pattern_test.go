@@ -0,0 +1,586 @@
+package path_mapper
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type File struct {
+	Owner string
+	Path  string
+}
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		success bool
+	}{
+		{
+			name:    "Placeholders",
+			pattern: "/{owner}/{repository}/issues/{number}",
+			success: true,
+		},
+		{
+			name:    "Catch-all",
+			pattern: "/{owner}/files/{path...}",
+			success: true,
+		},
+		{
+			name:    "Unnamed catch-all",
+			pattern: "/static/{...}",
+			success: true,
+		},
+		{
+			name:    "Catch-all is not the last segment",
+			pattern: "/{path...}/files",
+			success: false,
+		},
+		{
+			name:    "Placeholder has no name",
+			pattern: "/{}/files",
+			success: false,
+		},
+		{
+			name:    "Mixed segment",
+			pattern: "/logs/{day}.log",
+			success: true,
+		},
+		{
+			name:    "Adjacent placeholders in a mixed segment",
+			pattern: "/files/{name}{ext}",
+			success: false,
+		},
+		{
+			name:    "Catch-all in a mixed segment",
+			pattern: "/files/v{path...}",
+			success: false,
+		},
+		{
+			name:    "Unclosed brace",
+			pattern: "/files/{name.txt",
+			success: false,
+		},
+		{
+			name:    "Constraints",
+			pattern: "/{id:int}/{kind:issues|pulls}/{sha:[0-9a-f]{40}}/v{major:uint}",
+			success: true,
+		},
+		{
+			name:    "Invalid constraint",
+			pattern: "/{id:[0-9}",
+			success: false,
+		},
+		{
+			name:    "Catch-all with a constraint",
+			pattern: "/{path...:int}",
+			success: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile(tt.pattern)
+			if (err == nil) != tt.success {
+				t.Errorf("Compile() return (%v), which is not what we want.", err)
+			}
+		})
+	}
+}
+
+func TestPattern_Mapping(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		opts    []Option
+		path    string
+		want    *File
+		success bool
+	}{
+		{
+			name:    "Catch-all captures the rest of the path",
+			pattern: "/{owner}/files/{path...}",
+			path:    "/guest/files/docs/README.md",
+			want:    &File{Owner: "guest", Path: "docs/README.md"},
+			success: true,
+		},
+		{
+			name:    "Catch-all captures an empty segment",
+			pattern: "/{owner}/files/{path...}",
+			path:    "/guest/files/",
+			want:    &File{Owner: "guest", Path: ""},
+			success: true,
+		},
+		{
+			name:    "Captured values are percent-decoded",
+			pattern: "/{owner}/files/{path}",
+			path:    "/John%20Doe/files/docs%2FREADME.md",
+			want:    &File{Owner: "John Doe", Path: "docs/README.md"},
+			success: true,
+		},
+		{
+			name:    "Literal segments are compared with the decoded segment",
+			pattern: "/{owner}/my files/{path}",
+			path:    "/guest/my%20files/README.md",
+			want:    &File{Owner: "guest", Path: "README.md"},
+			success: true,
+		},
+		{
+			name:    "Escaped slash does not separate segments",
+			pattern: "/{owner}/files/{path}",
+			path:    "/guest/files/docs%2Fsub%2FREADME.md",
+			want:    &File{Owner: "guest", Path: "docs/sub/README.md"},
+			success: true,
+		},
+		{
+			name:    "Catch-all is decoded",
+			pattern: "/{owner}/files/{path...}",
+			path:    "/guest/files/my%20docs/README.md",
+			want:    &File{Owner: "guest", Path: "my docs/README.md"},
+			success: true,
+		},
+		{
+			name:    "Invalid escape",
+			pattern: "/{owner}/files/{path}",
+			path:    "/guest/files/100%",
+			success: false,
+		},
+		{
+			name:    "Without decoding",
+			pattern: "/{owner}/files/{path}",
+			opts:    []Option{WithoutDecoding()},
+			path:    "/John%20Doe/files/100%",
+			want:    &File{Owner: "John%20Doe", Path: "100%"},
+			success: true,
+		},
+		{
+			name:    "Catch-all needs at least one segment",
+			pattern: "/{owner}/files/{path...}",
+			path:    "/guest/files",
+			success: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := &File{}
+			err := MustCompile(tt.pattern, tt.opts...).Mapping(tt.path, got)
+			if (err == nil) != tt.success {
+				t.Fatalf("Mapping() return (%v), which is not what we want.", err)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Mapping() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPattern_Mapping_Errors(t *testing.T) {
+	p := MustCompile("/{owner}/{repository}/issues/{number}")
+
+	var mismatch *MismatchError
+	if err := p.Mapping("/guest/sandbox/pulls/1", &GitHubIssue{}); !errors.As(err, &mismatch) {
+		t.Errorf("Mapping() return (%v), want *MismatchError", err)
+	}
+
+	var bindErr *BindError
+	if err := p.Mapping("/guest/sandbox/issues/abc", &GitHubIssue{}); !errors.As(err, &bindErr) || bindErr.Name != "number" {
+		t.Errorf("Mapping() return (%v), want *BindError for number", err)
+	}
+}
+
+type FileName struct {
+	Name string
+	Ext  string
+}
+
+func TestPattern_Mapping_Mixed(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		path    string
+		want    *FileName
+		success bool
+	}{
+		{
+			name:    "Prefix and suffix",
+			pattern: "/files/v{name}.txt",
+			path:    "/files/v1.txt",
+			want:    &FileName{Name: "1"},
+			success: true,
+		},
+		{
+			name:    "Placeholder before a suffix takes everything up to it",
+			pattern: "/files/{name}.gz",
+			path:    "/files/a.tar.gz",
+			want:    &FileName{Name: "a.tar"},
+			success: true,
+		},
+		{
+			name:    "Other placeholders take the shortest match",
+			pattern: "/files/{name}.{ext}",
+			path:    "/files/a.tar.gz",
+			want:    &FileName{Name: "a", Ext: "tar.gz"},
+			success: true,
+		},
+		{
+			name:    "Values are decoded after matching",
+			pattern: "/files/{name}.{ext}",
+			path:    "/files/my%20file.txt",
+			want:    &FileName{Name: "my file", Ext: "txt"},
+			success: true,
+		},
+		{
+			name:    "Literal text does not match",
+			pattern: "/files/{name}.txt",
+			path:    "/files/a.md",
+			success: false,
+		},
+		{
+			name:    "Mixed segment does not span separators",
+			pattern: "/files/{name}.txt",
+			path:    "/files/a/b.txt",
+			success: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := &FileName{}
+			err := MustCompile(tt.pattern).Mapping(tt.path, got)
+			if (err == nil) != tt.success {
+				t.Fatalf("Mapping() return (%v), which is not what we want.", err)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Mapping() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPattern_Mapping_Constraint(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		path    string
+		want    *GitHubIssue
+		success bool
+	}{
+		{
+			name:    "Named constraint",
+			pattern: "/{owner}/{repository}/issues/{number:int}",
+			path:    "/guest/sandbox/issues/-1",
+			want:    &GitHubIssue{Owner: "guest", Repository: "sandbox", Number: -1},
+			success: true,
+		},
+		{
+			name:    "Value does not satisfy the constraint",
+			pattern: "/{owner}/{repository}/issues/{number:uint}",
+			path:    "/guest/sandbox/issues/-1",
+			success: false,
+		},
+		{
+			name:    "Regular expression",
+			pattern: "/{owner:[a-z]+}/{repository}",
+			path:    "/guest/sandbox",
+			want:    &GitHubIssue{Owner: "guest", Repository: "sandbox"},
+			success: true,
+		},
+		{
+			name:    "Regular expression matches the whole value",
+			pattern: "/{owner:[a-z]+}/{repository}",
+			path:    "/guest1/sandbox",
+			success: false,
+		},
+		{
+			name:    "Constraint sees the decoded value",
+			pattern: "/{owner:[a-z ]+}/{repository}",
+			path:    "/john%20doe/sandbox",
+			want:    &GitHubIssue{Owner: "john doe", Repository: "sandbox"},
+			success: true,
+		},
+		{
+			name:    "Constraint in a mixed segment",
+			pattern: "/{owner}/{repository}/issues/#{number:int}",
+			path:    "/guest/sandbox/issues/#1",
+			want:    &GitHubIssue{Owner: "guest", Repository: "sandbox", Number: 1},
+			success: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := &GitHubIssue{}
+			err := MustCompile(tt.pattern).Mapping(tt.path, got)
+			if (err == nil) != tt.success {
+				t.Fatalf("Mapping() return (%v), which is not what we want.", err)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Mapping() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+type API struct {
+	Version string
+}
+
+func TestMatchPrefix(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		path    string
+		want    *API
+		rest    string
+		success bool
+	}{
+		{
+			name:    "Rest of the path",
+			pattern: "/api/{version}",
+			path:    "/api/v1/users/John%20Doe",
+			want:    &API{Version: "v1"},
+			rest:    "/users/John%20Doe",
+			success: true,
+		},
+		{
+			name:    "Whole path",
+			pattern: "/api/{version}",
+			path:    "/api/v1",
+			want:    &API{Version: "v1"},
+			rest:    "",
+			success: true,
+		},
+		{
+			name:    "Trailing slash",
+			pattern: "/api/{version}",
+			path:    "/api/v1/",
+			want:    &API{Version: "v1"},
+			rest:    "/",
+			success: true,
+		},
+		{
+			name:    "Catch-all leaves nothing",
+			pattern: "/api/{version...}",
+			path:    "/api/v1/users",
+			want:    &API{Version: "v1/users"},
+			rest:    "",
+			success: true,
+		},
+		{
+			name:    "Pattern ends in a separator",
+			pattern: "/api/{version}/",
+			path:    "/api/v1/users",
+			want:    &API{Version: "v1"},
+			rest:    "/users",
+			success: true,
+		},
+		{
+			name:    "Pattern ends in a separator that the path ends in",
+			pattern: "/api/{version}/",
+			path:    "/api/v1/",
+			want:    &API{Version: "v1"},
+			rest:    "/",
+			success: true,
+		},
+		{
+			name:    "Path lacks the separator that the pattern ends in",
+			pattern: "/api/{version}/",
+			path:    "/api/v1",
+			success: false,
+		},
+		{
+			name:    "Root pattern",
+			pattern: "/",
+			path:    "/api/v1",
+			want:    &API{},
+			rest:    "/api/v1",
+			success: true,
+		},
+		{
+			name:    "Path is shorter than the pattern",
+			pattern: "/api/{version}",
+			path:    "/api",
+			success: false,
+		},
+		{
+			name:    "Prefix does not match",
+			pattern: "/api/{version}",
+			path:    "/web/v1/users",
+			success: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := &API{}
+			rest, err := MatchPrefix(tt.pattern, tt.path, got)
+			if (err == nil) != tt.success {
+				t.Fatalf("MatchPrefix() return (%v), which is not what we want.", err)
+			}
+			if err != nil {
+				return
+			}
+			if rest != tt.rest {
+				t.Errorf("MatchPrefix() rest = %q, want %q", rest, tt.rest)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("MatchPrefix() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMatchPrefix_Compose(t *testing.T) {
+	type User struct {
+		Name string
+	}
+
+	api := &API{}
+	rest, err := MatchPrefix("/api/{version}", "/api/v1/users/John%20Doe", api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &User{}
+	if err := Mapping("/users/{name}", rest, user); err != nil {
+		t.Fatal(err)
+	}
+	if api.Version != "v1" || user.Name != "John Doe" {
+		t.Errorf("MatchPrefix() and Mapping() mapped %+v and %+v", api, user)
+	}
+}
+
+type Org struct {
+	Org string
+}
+
+type OrgIssue struct {
+	Org
+	Repo   string
+	Number int
+}
+
+func TestPattern_Join(t *testing.T) {
+	tests := []struct {
+		name    string
+		base    string
+		pattern string
+		want    string
+		success bool
+	}{
+		{
+			name:    "Join",
+			base:    "/orgs/{org}",
+			pattern: "/repos/{repo}/issues/{number}",
+			want:    "/orgs/{org}/repos/{repo}/issues/{number}",
+			success: true,
+		},
+		{
+			name:    "Trailing slash of the base is shared",
+			base:    "/orgs/{org}/",
+			pattern: "/repos/{repo}",
+			want:    "/orgs/{org}/repos/{repo}",
+			success: true,
+		},
+		{
+			name:    "Empty pattern",
+			base:    "/orgs/{org}",
+			pattern: "",
+			want:    "/orgs/{org}",
+			success: true,
+		},
+		{
+			name:    "Pattern without a leading slash",
+			base:    "/orgs/{org}",
+			pattern: "repos/{repo}",
+			success: false,
+		},
+		{
+			name:    "Base ends in a catch-all",
+			base:    "/orgs/{path...}",
+			pattern: "/repos/{repo}",
+			success: false,
+		},
+		{
+			name:    "Duplicate placeholder",
+			base:    "/orgs/{org}",
+			pattern: "/repos/{org}",
+			success: false,
+		},
+		{
+			name:    "Invalid pattern",
+			base:    "/orgs/{org}",
+			pattern: "/{}",
+			success: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MustCompile(tt.base).Join(tt.pattern)
+			if (err == nil) != tt.success {
+				t.Fatalf("Join() return (%v), which is not what we want.", err)
+			}
+			if err == nil && got.String() != tt.want {
+				t.Errorf("Join() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPattern_Join_Mapping(t *testing.T) {
+	base := MustCompile("/orgs/{org}")
+	issues := base.MustJoin("/repos/{repo}/issues/{number}")
+
+	got := &OrgIssue{}
+	if err := issues.Mapping("/orgs/golang/repos/go/issues/1", got); err != nil {
+		t.Fatal(err)
+	}
+	want := &OrgIssue{Org: Org{Org: "golang"}, Repo: "go", Number: 1}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Mapping() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPattern_Constraints(t *testing.T) {
+	p := MustCompile("/{owner}/{kind:issues|pulls}/p{page:uint}-{offset:int}/{path...}")
+	if diff := cmp.Diff([]string{"owner", "kind", "page", "offset", "path"}, p.Names()); diff != "" {
+		t.Errorf("Names() mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"", "issues|pulls", "uint", "int", ""}, p.Constraints()); diff != "" {
+		t.Errorf("Constraints() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPattern_Equivalent(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{a: "/users/{id}", b: "/users/{name}", want: true},
+		{a: "/users/{id:int}/p{page}", b: "/users/{n:int}/p{p}", want: true},
+		{a: "/users/{id:int}", b: "/users/{id}", want: false},
+		{a: "/users/{id}", b: "/users/{id}/", want: false},
+		{a: "/users/{id}", b: "/groups/{id}", want: false},
+		{a: "/files/{path...}", b: "/files/{rest...}", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			if got := MustCompile(tt.a).Equivalent(MustCompile(tt.b)); got != tt.want {
+				t.Errorf("Equivalent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,469 @@
+package path_mapper
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Regexp returns an anchored regular expression that matches the same paths as the pattern,
+// with a group named after each named placeholder that captures its value as it appears in the path,
+// before percent-decoding.
+//
+// Where the pattern decodes paths, every character of a literal or of a constraint also matches
+// in its percent-encoded form. When the pattern normalizes paths, the expression matches the paths
+// returned by NormalizePath. Constraints are assumed to see values that are valid UTF-8 once decoded.
+//
+// Regexp fails if the pattern cannot be expressed exactly: if a placeholder name is not a valid group name,
+// if a constraint uses anchors or word boundaries, or if a placeholder of a mixed segment
+// is followed by literal text of more than one character that is not the suffix of the segment.
+func (p *Pattern) Regexp() (*regexp.Regexp, error) {
+	w := &regexpWriter{o: &p.opts}
+	w.b.WriteString("^")
+	for _, s := range p.segments {
+		if s.sep != 0 {
+			w.b.WriteString(regexp.QuoteMeta(string(s.sep)))
+		}
+		if err := w.segment(s); err != nil {
+			return nil, fmt.Errorf("pattern(%v): %w", p.raw, err)
+		}
+	}
+	w.b.WriteString("$")
+	return regexp.Compile(w.b.String())
+}
+
+// MustRegexp is like Regexp but panics if the pattern cannot be expressed as a regular expression.
+func (p *Pattern) MustRegexp() *regexp.Regexp {
+	re, err := p.Regexp()
+	if err != nil {
+		panic(err)
+	}
+	return re
+}
+
+// regexpWriter writes the regular expression of a pattern to b.
+type regexpWriter struct {
+	o *options
+	b strings.Builder
+}
+
+// anyRune is the character class of every rune.
+var anyRune = []rune{0, unicode.MaxRune}
+
+// neverMatch matches nothing.
+const neverMatch = `[^\x00-\x{10FFFF}]`
+
+func (w *regexpWriter) segment(s segment) error {
+	switch s.kind {
+	case literalSegment:
+		w.literal(s.value)
+	case placeholderSegment:
+		return w.placeholder(s, nil)
+	case mixedSegment:
+		for i, part := range s.parts {
+			if part.kind == literalSegment {
+				w.literal(part.value)
+				continue
+			}
+
+			// A placeholder followed by more than a suffix ends at the first occurrence of the following text,
+			// so its value must not contain that text.
+			var excluded []rune
+			if i+2 < len(s.parts) {
+				next := s.parts[i+1].value
+				r, size := utf8.DecodeRuneInString(next)
+				if size != len(next) {
+					return fmt.Errorf("placeholder %v of %v is followed by more than one character", part, s)
+				}
+				excluded = w.variants(r)
+			}
+			if err := w.placeholder(part, excluded); err != nil {
+				return err
+			}
+		}
+	case catchAllSegment:
+		expr := `(?s:.*)`
+		if !w.o.noDecoding {
+			expr = `(?:[^%]|%[0-9A-Fa-f]{2})*`
+		}
+		return w.group(s.value, expr)
+	}
+	return nil
+}
+
+func (w *regexpWriter) group(name, expr string) error {
+	if name == "" {
+		w.b.WriteString("(?:" + expr + ")")
+		return nil
+	}
+	for _, r := range name {
+		if !(r == '_' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9') {
+			return fmt.Errorf("placeholder name %v is not a valid group name", name)
+		}
+	}
+	w.b.WriteString("(?P<" + name + ">" + expr + ")")
+	return nil
+}
+
+// literal writes literal text, each character of which may be escaped if the pattern decodes paths.
+func (w *regexpWriter) literal(text string) {
+	for _, r := range text {
+		expr, _ := w.unit(w.variants(r), nil)
+		w.b.WriteString(expr)
+	}
+}
+
+// variants returns the runes that are equal to r in a literal, as ranges.
+func (w *regexpWriter) variants(r rune) []rune {
+	var runes []rune
+	switch {
+	case w.o.literalCase == IgnoreASCIICase && 'a' <= r && r <= 'z':
+		runes = []rune{r - 'a' + 'A', r}
+	case w.o.literalCase == IgnoreASCIICase && 'A' <= r && r <= 'Z':
+		runes = []rune{r, r - 'A' + 'a'}
+	case w.o.literalCase == FoldCase:
+		runes = foldOrbit(r)
+	default:
+		runes = []rune{r}
+	}
+	ranges := make([]rune, 0, 2*len(runes))
+	for _, r := range runes {
+		ranges = append(ranges, r, r)
+	}
+	return ranges
+}
+
+// foldOrbit returns the runes that are equal to r under simple case folding, in increasing order.
+func foldOrbit(r rune) []rune {
+	runes := []rune{r}
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		runes = append(runes, f)
+	}
+	for i := 1; i < len(runes); i++ {
+		for j := i; j > 0 && runes[j] < runes[j-1]; j-- {
+			runes[j], runes[j-1] = runes[j-1], runes[j]
+		}
+	}
+	return runes
+}
+
+// placeholder writes the group of a placeholder whose decoded value contains none of the excluded runes.
+func (w *regexpWriter) placeholder(s segment, excluded []rune) error {
+	if s.constraint != nil {
+		re, err := syntax.Parse(s.constraint.expr, syntax.Perl)
+		if err != nil {
+			return err
+		}
+		// The constraint is anchored anyway, so anchors around all of it can be dropped.
+		if re.Op == syntax.OpConcat && len(re.Sub) > 1 &&
+			re.Sub[0].Op == syntax.OpBeginText && re.Sub[len(re.Sub)-1].Op == syntax.OpEndText {
+			re.Sub = re.Sub[1 : len(re.Sub)-1]
+		}
+		expr, _, err := w.node(re, excluded)
+		if err != nil {
+			return fmt.Errorf("constraint of %v: %w", s, err)
+		}
+		return w.group(s.value, expr)
+	}
+
+	// Without a constraint, any escape is accepted, even one that does not decode to valid UTF-8.
+	raw := subtractRanges(anyRune, excluded)
+	if w.o.noDecoding {
+		return w.group(s.value, w.rawClass(raw)+"*")
+	}
+	var bytes [256]bool
+	for i := range bytes {
+		bytes[i] = true
+	}
+	for i := 0; i+1 < len(excluded); i += 2 {
+		for r := excluded[i]; r <= excluded[i+1]; r++ {
+			if r >= utf8.RuneSelf {
+				return fmt.Errorf("placeholder %v is followed by a character that is not ASCII", s)
+			}
+			bytes[r] = false
+		}
+	}
+	return w.group(s.value, "(?:"+w.rawClass(raw)+"|%"+hexClass(&bytes)+")*")
+}
+
+// rawClass returns the character class of the runes that can appear unescaped in a value.
+func (w *regexpWriter) rawClass(ranges []rune) string {
+	seps := w.o.separators()
+	if !w.o.noDecoding {
+		seps += "%"
+	}
+	for i := 0; i < len(seps); i++ {
+		ranges = subtractRanges(ranges, []rune{rune(seps[i]), rune(seps[i])})
+	}
+	if len(ranges) == 0 {
+		return ""
+	}
+	if len(ranges) == 2 && ranges[0] == ranges[1] && unicode.IsPrint(ranges[0]) {
+		return regexp.QuoteMeta(string(ranges[0]))
+	}
+	return (&syntax.Regexp{Op: syntax.OpCharClass, Rune: ranges}).String()
+}
+
+// unit returns the expression of a single decoded character among ranges, other than the excluded runes.
+// It reports whether the expression is atomic, so that a repetition can follow it without parentheses.
+func (w *regexpWriter) unit(ranges, excluded []rune) (string, bool) {
+	ranges = subtractRanges(ranges, excluded)
+	var alternatives []string
+	if raw := w.rawClass(ranges); raw != "" {
+		alternatives = append(alternatives, raw)
+	}
+	if !w.o.noDecoding {
+		alternatives = append(alternatives, escapedRanges(ranges)...)
+	}
+	switch len(alternatives) {
+	case 0:
+		return neverMatch, true
+	case 1:
+		return alternatives[0], !strings.HasPrefix(alternatives[0], "%")
+	}
+	return "(?:" + strings.Join(alternatives, "|") + ")", true
+}
+
+// node returns the expression that matches the escaped forms of the values that re matches,
+// other than those containing an excluded rune.
+func (w *regexpWriter) node(re *syntax.Regexp, excluded []rune) (expr string, atomic bool, err error) {
+	switch re.Op {
+	case syntax.OpNoMatch:
+		return neverMatch, true, nil
+	case syntax.OpEmptyMatch:
+		return "", false, nil
+	case syntax.OpLiteral:
+		var b strings.Builder
+		for _, r := range re.Rune {
+			ranges := []rune{r, r}
+			if re.Flags&syntax.FoldCase != 0 {
+				ranges = nil
+				for _, f := range foldOrbit(r) {
+					ranges = append(ranges, f, f)
+				}
+			}
+			expr, atomic = w.unit(ranges, excluded)
+			b.WriteString(expr)
+		}
+		return b.String(), atomic && len(re.Rune) == 1, nil
+	case syntax.OpCharClass:
+		expr, atomic = w.unit(re.Rune, excluded)
+		return expr, atomic, nil
+	case syntax.OpAnyCharNotNL:
+		expr, atomic = w.unit([]rune{0, '\n' - 1, '\n' + 1, unicode.MaxRune}, excluded)
+		return expr, atomic, nil
+	case syntax.OpAnyChar:
+		expr, atomic = w.unit(anyRune, excluded)
+		return expr, atomic, nil
+	case syntax.OpCapture:
+		return w.node(re.Sub[0], excluded)
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		sub, atomic, err := w.node(re.Sub[0], excluded)
+		if err != nil {
+			return "", false, err
+		}
+		if !atomic {
+			sub = "(?:" + sub + ")"
+		}
+		switch re.Op {
+		case syntax.OpStar:
+			return sub + "*", false, nil
+		case syntax.OpPlus:
+			return sub + "+", false, nil
+		case syntax.OpQuest:
+			return sub + "?", false, nil
+		}
+		if re.Max < 0 {
+			return fmt.Sprintf("%v{%d,}", sub, re.Min), false, nil
+		}
+		if re.Max == re.Min {
+			return fmt.Sprintf("%v{%d}", sub, re.Min), false, nil
+		}
+		return fmt.Sprintf("%v{%d,%d}", sub, re.Min, re.Max), false, nil
+	case syntax.OpConcat, syntax.OpAlternate:
+		subs := make([]string, len(re.Sub))
+		for i, sub := range re.Sub {
+			if subs[i], atomic, err = w.node(sub, excluded); err != nil {
+				return "", false, err
+			}
+		}
+		if re.Op == syntax.OpAlternate {
+			return "(?:" + strings.Join(subs, "|") + ")", true, nil
+		}
+		return strings.Join(subs, ""), len(subs) == 1 && atomic, nil
+	}
+	return "", false, errors.New("anchors and word boundaries cannot be used")
+}
+
+// subtractRanges returns the ranges of runes in a but not in b. Both are sorted pairs of ranges.
+func subtractRanges(a, b []rune) []rune {
+	if len(b) == 0 {
+		return a
+	}
+	var out []rune
+	for i := 0; i+1 < len(a); i += 2 {
+		lo, hi := a[i], a[i+1]
+		for j := 0; j+1 < len(b) && lo <= hi; j += 2 {
+			if b[j+1] < lo || b[j] > hi {
+				continue
+			}
+			if b[j] > lo {
+				out = append(out, lo, b[j]-1)
+			}
+			lo = b[j+1] + 1
+		}
+		if lo <= hi {
+			out = append(out, lo, hi)
+		}
+	}
+	return out
+}
+
+// escapedRanges returns the percent-encoded forms of the runes in ranges, one per alternative.
+func escapedRanges(ranges []rune) []string {
+	var ascii [256]bool
+	var hasASCII bool
+	var alternatives []string
+	for i := 0; i+1 < len(ranges); i += 2 {
+		for r := ranges[i]; r <= ranges[i+1] && r < utf8.RuneSelf; r++ {
+			ascii[r], hasASCII = true, true
+		}
+		if ranges[i+1] < utf8.RuneSelf {
+			continue
+		}
+		utf8Ranges(max(ranges[i], utf8.RuneSelf), ranges[i+1], func(bytes [][2]byte) {
+			var b strings.Builder
+			for _, r := range bytes {
+				var set [256]bool
+				for c := int(r[0]); c <= int(r[1]); c++ {
+					set[c] = true
+				}
+				b.WriteString("%" + hexClass(&set))
+			}
+			alternatives = append(alternatives, b.String())
+		})
+	}
+	if hasASCII {
+		alternatives = append([]string{"%" + hexClass(&ascii)}, alternatives...)
+	}
+	return alternatives
+}
+
+// hexClass returns the expression of the two hexadecimal digits of the bytes in set, in either case.
+func hexClass(set *[256]bool) string {
+	// Group the high digits by the set of low digits that follow them.
+	var lows [16]uint16
+	for c, ok := range set {
+		if ok {
+			lows[c>>4] |= 1 << (c & 15)
+		}
+	}
+	var alternatives []string
+	done := make(map[uint16]bool)
+	for hi, low := range lows {
+		if low == 0 || done[low] {
+			continue
+		}
+		done[low] = true
+		var highs uint16
+		for h := hi; h < 16; h++ {
+			if lows[h] == low {
+				highs |= 1 << h
+			}
+		}
+		alternatives = append(alternatives, nibbleClass(highs)+nibbleClass(low))
+	}
+	if len(alternatives) == 1 {
+		return alternatives[0]
+	}
+	return "(?:" + strings.Join(alternatives, "|") + ")"
+}
+
+// nibbleClass returns the character class of the hexadecimal digits in set, in either case.
+func nibbleClass(set uint16) string {
+	const digits = "0123456789ABCDEF"
+	var b strings.Builder
+	n := 0
+	for lo := 0; lo < 16; lo++ {
+		if set&(1<<lo) == 0 {
+			continue
+		}
+		// Runs of digits and runs of letters are written separately, so that letters can be given in both cases.
+		hi := lo
+		for hi+1 < 16 && hi+1 != 10 && set&(1<<(hi+1)) != 0 {
+			hi++
+		}
+		for _, d := range []string{digits, strings.ToLower(digits)} {
+			b.WriteByte(d[lo])
+			if hi > lo+1 {
+				b.WriteByte('-')
+			}
+			if hi > lo {
+				b.WriteByte(d[hi])
+			}
+			if lo < 10 {
+				break
+			}
+		}
+		n += hi - lo + 1
+		lo = hi
+	}
+	if n == 1 && set&0x3ff != 0 {
+		return b.String()
+	}
+	return "[" + b.String() + "]"
+}
+
+// utf8Ranges calls fn with the byte ranges of the UTF-8 encodings of the runes from lo to hi,
+// split so that every combination of bytes within the ranges is the encoding of one of the runes.
+func utf8Ranges(lo, hi rune, fn func([][2]byte)) {
+	if hi > unicode.MaxRune {
+		hi = unicode.MaxRune
+	}
+	if lo > hi {
+		return
+	}
+	// Surrogates have no encoding.
+	if lo <= 0xDFFF && hi >= 0xD800 {
+		utf8Ranges(lo, 0xD7FF, fn)
+		utf8Ranges(0xE000, hi, fn)
+		return
+	}
+	for _, m := range []rune{0x7F, 0x7FF, 0xFFFF} {
+		if lo <= m && hi > m {
+			utf8Ranges(lo, m, fn)
+			utf8Ranges(m+1, hi, fn)
+			return
+		}
+	}
+	n := utf8.RuneLen(lo)
+	for i := 1; i < n; i++ {
+		m := rune(1)<<(6*i) - 1
+		if lo&^m == hi&^m {
+			continue
+		}
+		if lo&m != 0 {
+			utf8Ranges(lo, lo|m, fn)
+			utf8Ranges((lo|m)+1, hi, fn)
+			return
+		}
+		if hi&m != m {
+			utf8Ranges(lo, (hi&^m)-1, fn)
+			utf8Ranges(hi&^m, hi, fn)
+			return
+		}
+	}
+
+	var a, b [utf8.UTFMax]byte
+	utf8.EncodeRune(a[:], lo)
+	utf8.EncodeRune(b[:], hi)
+	ranges := make([][2]byte, n)
+	for i := range ranges {
+		ranges[i] = [2]byte{a[i], b[i]}
+	}
+	fn(ranges)
+}
@@ -0,0 +1,178 @@
+package path_mapper
+
+import (
+	"math/rand"
+	"net/url"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPattern_Regexp(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		opts    []Option
+		want    string
+		success bool
+	}{
+		{
+			name:    "Placeholders and constraints",
+			pattern: "/{owner}/{repository}/issues/{number:int}",
+			opts:    []Option{WithoutDecoding()},
+			want:    `^/(?P<owner>[^/]*)/(?P<repository>[^/]*)/issues/(?P<number>-?[0-9]+)$`,
+			success: true,
+		},
+		{
+			name:    "Mixed segment",
+			pattern: "/files/{name}.{ext}",
+			opts:    []Option{WithoutDecoding()},
+			want:    `^/files/(?P<name>[^\./]*)\.(?P<ext>[^/]*)$`,
+			success: true,
+		},
+		{
+			name:    "Catch-all and other separators",
+			pattern: "arn:aws:s3:::{bucket}/{...}",
+			opts:    []Option{WithoutDecoding(), WithSeparators(":/")},
+			want:    `^arn:aws:s3:::(?P<bucket>[^/:]*)/(?:(?s:.*))$`,
+			success: true,
+		},
+		{
+			name:    "Escaped characters",
+			pattern: "/a/{id:uint}",
+			want:    `^/(?:a|%61)/(?P<id>(?:[0-9]|%3[0-9])+)$`,
+			success: true,
+		},
+		{
+			name:    "Case-insensitive literals",
+			pattern: "/Api",
+			opts:    []Option{WithoutDecoding(), WithLiteralCase(IgnoreASCIICase)},
+			want:    `^/[Aa][Pp][Ii]$`,
+			success: true,
+		},
+		{
+			name:    "Anchored constraint",
+			pattern: "/{id:^[0-9]+$}",
+			opts:    []Option{WithoutDecoding()},
+			want:    `^/(?P<id>[0-9]+)$`,
+			success: true,
+		},
+		{
+			name:    "Anchor inside a constraint",
+			pattern: "/{id:a|^b}",
+			success: false,
+		},
+		{
+			name:    "Placeholder followed by more than one character",
+			pattern: "/{from}..{to}.txt",
+			success: false,
+		},
+		{
+			name:    "Name that is not a group name",
+			pattern: "/{repository-name}",
+			success: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re, err := MustCompile(tt.pattern, tt.opts...).Regexp()
+			if (err == nil) != tt.success {
+				t.Fatalf("Regexp() return (%v), which is not what we want.", err)
+			}
+			if err == nil && re.String() != tt.want {
+				t.Errorf("Regexp() = %v, want %v", re, tt.want)
+			}
+		})
+	}
+}
+
+// TestPattern_Regexp_Equivalence checks that the regular expression of a pattern matches the same paths
+// as the pattern, and captures the same values.
+func TestPattern_Regexp_Equivalence(t *testing.T) {
+	patterns := []struct {
+		pattern string
+		opts    []Option
+	}{
+		{pattern: "/{owner}/{repository}/issues/{number:int}"},
+		{pattern: "/{owner}/{repository}/issues/{number:int}", opts: []Option{WithoutDecoding()}},
+		{pattern: "/files/{name}.{ext}"},
+		{pattern: "/files/{name}.{ext}", opts: []Option{WithoutDecoding()}},
+		{pattern: "/files/v{major:uint}.{minor:uint}.gz"},
+		{pattern: "/files/{path...}"},
+		{pattern: "/{...}", opts: []Option{WithoutDecoding()}},
+		{pattern: "/a/{kind:a|b+|[^a-z]}"},
+		{pattern: "/{x:.}/{y:(?i)ä}"},
+		{pattern: "/a/B/ä", opts: []Option{WithLiteralCase(IgnoreASCIICase)}},
+		{pattern: "/a/B/ä/{x}.b", opts: []Option{WithLiteralCase(FoldCase)}},
+		{pattern: "a:{id}:b", opts: []Option{WithSeparators(":"), WithoutDecoding()}},
+		{pattern: "a:{id}/{rest...}", opts: []Option{WithSeparators(":/")}},
+		{pattern: "/", opts: []Option{WithNormalization(IgnoreTrailingSlash)}},
+		{pattern: "/a/{b}/", opts: []Option{WithNormalization(IgnoreTrailingSlash)}},
+	}
+	tokens := []string{
+		"/", "/", "/", ":", "a", "A", "b", "B", "bb", "1", "-", ".", "..", "gz", "v", "x", "files", "issues",
+		"%2F", "%2f", "%2E", "%41", "%61", "%62", "%31", "%", "%G1", "ä", "Ä", "%C3%A4", "%C3%84", "%FF", "%0A",
+	}
+	r := rand.New(rand.NewSource(1))
+	var paths []string
+	for i := 0; i < 20000; i++ {
+		var b strings.Builder
+		for n := r.Intn(8); n >= 0; n-- {
+			b.WriteString(tokens[r.Intn(len(tokens))])
+		}
+		paths = append(paths, b.String())
+	}
+
+	for _, tt := range patterns {
+		p := MustCompile(tt.pattern, tt.opts...)
+		re := p.MustRegexp()
+		// Paths built from the pattern make sure that every pattern matches some of them.
+		candidates := append([]string{examplePath(p.segments, &p.opts)}, paths...)
+		for i := 0; i < 2000; i++ {
+			candidates = append(candidates, strings.ReplaceAll(examplePath(p.segments, &p.opts), "a", tokens[r.Intn(len(tokens))]))
+		}
+
+		matched := 0
+		for _, path := range candidates {
+			path = NormalizePath(path, p.opts.normalization)
+			if decoded, err := url.PathUnescape(path); err == nil && !utf8.ValidString(decoded) {
+				// Regexp assumes that escapes decode to valid UTF-8.
+				continue
+			}
+			names, values, ok := p.matchNormalized(path)
+			groups := re.FindStringSubmatch(path)
+			if ok != (groups != nil) {
+				t.Fatalf("pattern(%v) matches %q: %v, but %v matches: %v", p, path, ok, re, groups != nil)
+			}
+			if !ok {
+				continue
+			}
+			matched++
+
+			want := make(map[string]string)
+			for i, name := range names {
+				if name != "" {
+					want[name] = values[i]
+				}
+			}
+			got := make(map[string]string)
+			for i, name := range re.SubexpNames() {
+				if name == "" {
+					continue
+				}
+				if got[name] = groups[i]; !p.opts.noDecoding {
+					got[name], _ = url.PathUnescape(groups[i])
+				}
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Fatalf("pattern(%v) and %v capture different values from %q (-want +got):\n%s", p, re, path, diff)
+			}
+		}
+		if matched == 0 {
+			t.Errorf("pattern(%v) matches none of the paths", p)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package path_mapper
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// FromRequest maps the wildcards matched by http.ServeMux into dest.
+// Each field receives r.PathValue of the name it would be mapped from by Mapping;
+// fields whose wildcard is absent or empty are left untouched.
+func FromRequest(r *http.Request, dest interface{}) error {
+	v := reflect.ValueOf(dest)
+
+	if v.Kind() != reflect.Ptr {
+		return errors.New("must pass a pointer, not a value, to dest")
+	}
+
+	if v.IsNil() {
+		return errors.New("must pass non-nil pointer to dest")
+	}
+
+	if reflect.Indirect(v).Kind() != reflect.Struct {
+		return errors.New("argument not a struct")
+	}
+
+	fields := make([]string, 0, len(fieldMapper.TypeMap(v.Type()).Names))
+	for name := range fieldMapper.TypeMap(v.Type()).Names {
+		fields = append(fields, name)
+	}
+	sort.Strings(fields)
+
+	var names, values []string
+	for _, name := range fields {
+		if value := r.PathValue(name); value != "" {
+			names = append(names, name)
+			values = append(values, value)
+		}
+	}
+	return bind(names, values, dest)
+}
+
+// ServeMuxPattern is a pattern of http.ServeMux translated into a Pattern.
+type ServeMuxPattern struct {
+	// Method is the method the pattern is restricted to, or empty if it matches any method.
+	Method string
+	// Host is the host the pattern is restricted to, or empty if it matches any host.
+	Host string
+	// Pattern matches the same paths as the path part of the ServeMux pattern.
+	Pattern *Pattern
+}
+
+// ParseServeMuxPattern translates a pattern of http.ServeMux, such as "GET /{owner}/{repository}/issues/{number}".
+//
+// The wildcards {name} and {name...} are kept as they are.
+// A path ending in "/{$}" matches only the path ending in the slash, so it becomes a literal empty segment.
+// Any other path ending in a slash matches every path below it, so it gets an unnamed catch-all "{...}".
+func ParseServeMuxPattern(s string) (*ServeMuxPattern, error) {
+	p := &ServeMuxPattern{}
+	rest := strings.TrimLeft(s, " \t")
+	if i := strings.IndexAny(rest, " \t"); i >= 0 {
+		p.Method, rest = rest[:i], strings.TrimLeft(rest[i+1:], " \t")
+	}
+
+	i := strings.IndexByte(rest, '/')
+	if i < 0 {
+		return nil, fmt.Errorf("ServeMux pattern(%v): host/path missing /", s)
+	}
+	p.Host, rest = rest[:i], rest[i:]
+
+	switch {
+	case strings.HasSuffix(rest, "/{$}"):
+		rest = strings.TrimSuffix(rest, "{$}")
+	case strings.HasSuffix(rest, "/"):
+		rest += "{...}"
+	}
+	if strings.Contains(rest, "{$}") {
+		return nil, fmt.Errorf("ServeMux pattern(%v): {$} must be at the end", s)
+	}
+
+	pattern, err := Compile(rest)
+	if err != nil {
+		return nil, err
+	}
+	p.Pattern = pattern
+	return p, nil
+}
@@ -0,0 +1,154 @@
+package path_mapper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type ServeMuxFile struct {
+	Owner string
+	Path  string `alias:"file"`
+}
+
+func TestFromRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		path    string
+		dest    interface{}
+		want    interface{}
+		success bool
+	}{
+		{
+			name:    "Wildcards",
+			pattern: "GET /{owner}/{repository}/issues/{number}",
+			path:    "/KamikazeZirou/path-mapper/issues/1",
+			dest:    &GitHubIssue{},
+			want:    &GitHubIssue{Owner: "KamikazeZirou", Repository: "path-mapper", Number: 1},
+			success: true,
+		},
+		{
+			name:    "Remaining wildcard and alias",
+			pattern: "/{owner}/files/{file...}",
+			path:    "/guest/files/docs/README.md",
+			dest:    &ServeMuxFile{},
+			want:    &ServeMuxFile{Owner: "guest", Path: "docs/README.md"},
+			success: true,
+		},
+		{
+			name:    "Wildcard cannot be converted",
+			pattern: "/{owner}/{repository}/issues/{number}",
+			path:    "/KamikazeZirou/path-mapper/issues/abc",
+			dest:    &GitHubIssue{},
+			success: false,
+		},
+		{
+			name:    "dest is value",
+			pattern: "/{owner}/{repository}/issues/{number}",
+			path:    "/KamikazeZirou/path-mapper/issues/1",
+			dest:    GitHubIssue{},
+			success: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			mux := http.NewServeMux()
+			mux.HandleFunc(tt.pattern, func(w http.ResponseWriter, r *http.Request) {
+				err = FromRequest(r, tt.dest)
+			})
+			mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if (err == nil) != tt.success {
+				t.Fatalf("FromRequest() return (%v), which is not what we want.", err)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, tt.dest); diff != "" {
+				t.Errorf("FromRequest() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseServeMuxPattern(t *testing.T) {
+	type want struct {
+		method  string
+		host    string
+		pattern string
+	}
+
+	tests := []struct {
+		name    string
+		pattern string
+		want    want
+		success bool
+	}{
+		{
+			name:    "Method and wildcards",
+			pattern: "GET /{owner}/{repository}/issues/{number}",
+			want:    want{method: "GET", pattern: "/{owner}/{repository}/issues/{number}"},
+			success: true,
+		},
+		{
+			name:    "Host and remaining wildcard",
+			pattern: "example.com/{owner}/files/{path...}",
+			want:    want{host: "example.com", pattern: "/{owner}/files/{path...}"},
+			success: true,
+		},
+		{
+			name:    "End of path",
+			pattern: "POST /{owner}/{$}",
+			want:    want{method: "POST", pattern: "/{owner}/"},
+			success: true,
+		},
+		{
+			name:    "Trailing slash matches the subtree",
+			pattern: "/static/",
+			want:    want{pattern: "/static/{...}"},
+			success: true,
+		},
+		{
+			name:    "No path",
+			pattern: "GET example.com",
+			success: false,
+		},
+		{
+			name:    "{$} in the middle",
+			pattern: "/{$}/x",
+			success: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseServeMuxPattern(tt.pattern)
+			if (err == nil) != tt.success {
+				t.Fatalf("ParseServeMuxPattern() return (%v), which is not what we want.", err)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, want{method: got.Method, host: got.Host, pattern: got.Pattern.String()}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("ParseServeMuxPattern() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseServeMuxPattern_MatchesLikeServeMux(t *testing.T) {
+	p, err := ParseServeMuxPattern("/static/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"/static/", "/static/css/site.css"} {
+		if err := p.Pattern.Mapping(path, &struct{}{}); err != nil {
+			t.Errorf("Mapping(%v) return (%v)", path, err)
+		}
+	}
+}
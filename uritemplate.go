@@ -0,0 +1,628 @@
+package path_mapper
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/KamikazeZirou/path-mapper/internal/reflectx"
+)
+
+// URITemplate is a URI template as defined by RFC 6570, up to level 4,
+// such as "/repos{/owner,repo}{?page,per_page}".
+//
+// Variables are read from and mapped into the fields of a structure under the same names as placeholders.
+// A slice is a list value and a map is an associative array. A field that is missing, nil, empty
+// or the zero value of its type is undefined, and so is left out of the expansion.
+type URITemplate struct {
+	raw   string
+	parts []templatePart
+	// re matches the expansions of a reversible template. It is nil if the template is not reversible.
+	re *regexp.Regexp
+	// irreversible explains why the template is not reversible.
+	irreversible error
+}
+
+// templatePart is a literal or an expression of a URI template.
+type templatePart struct {
+	literal string
+	// op is the operator of an expression. It is nil for a literal.
+	op   *templateOperator
+	vars []templateVar
+}
+
+type templateVar struct {
+	name string
+	// prefix is the maximum number of characters to expand, or 0 to expand the whole value.
+	prefix  int
+	explode bool
+}
+
+// templateOperator is a row of the expansion table in RFC 6570, appendix A.
+type templateOperator struct {
+	first   string
+	sep     string
+	named   bool
+	ifEmpty string
+	// reserved allows reserved characters and escapes in values.
+	reserved bool
+}
+
+var templateOperators = map[byte]*templateOperator{
+	0:   {first: "", sep: ","},
+	'+': {first: "", sep: ",", reserved: true},
+	'.': {first: ".", sep: "."},
+	'/': {first: "/", sep: "/"},
+	';': {first: ";", sep: ";", named: true},
+	'?': {first: "?", sep: "&", named: true, ifEmpty: "="},
+	'&': {first: "&", sep: "&", named: true, ifEmpty: "="},
+	'#': {first: "#", sep: ",", reserved: true},
+}
+
+// ParseURITemplate parses an RFC 6570 URI template.
+func ParseURITemplate(template string) (*URITemplate, error) {
+	t := &URITemplate{raw: template}
+	for rest := template; rest != ""; {
+		open := strings.IndexByte(rest, '{')
+		if open < 0 {
+			open = len(rest)
+		}
+		if strings.IndexByte(rest[:open], '}') >= 0 {
+			return nil, fmt.Errorf("URI template(%v): unexpected }", template)
+		}
+		if open > 0 {
+			t.parts = append(t.parts, templatePart{literal: rest[:open]})
+		}
+		if open == len(rest) {
+			break
+		}
+
+		end := strings.IndexByte(rest[open:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("URI template(%v): unclosed {", template)
+		}
+		part, err := parseTemplateExpression(rest[open+1 : open+end])
+		if err != nil {
+			return nil, fmt.Errorf("URI template(%v): %w", template, err)
+		}
+		t.parts = append(t.parts, part)
+		rest = rest[open+end+1:]
+	}
+
+	if t.irreversible = t.checkReversible(); t.irreversible == nil {
+		t.re = regexp.MustCompile(t.regexp())
+	}
+	return t, nil
+}
+
+// MustParseURITemplate is like ParseURITemplate but panics if the template cannot be parsed.
+func MustParseURITemplate(template string) *URITemplate {
+	t, err := ParseURITemplate(template)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func parseTemplateExpression(expr string) (templatePart, error) {
+	if expr == "" {
+		return templatePart{}, errors.New("empty expression")
+	}
+	var part templatePart
+	if op, ok := templateOperators[expr[0]]; ok && expr[0] != 0 {
+		part.op, expr = op, expr[1:]
+	} else if strings.IndexByte("=,!@|", expr[0]) >= 0 {
+		return templatePart{}, fmt.Errorf("operator %c is reserved", expr[0])
+	} else {
+		part.op = templateOperators[0]
+	}
+
+	for _, spec := range strings.Split(expr, ",") {
+		var v templateVar
+		if strings.HasSuffix(spec, "*") {
+			v.explode, spec = true, strings.TrimSuffix(spec, "*")
+		} else if i := strings.IndexByte(spec, ':'); i >= 0 {
+			n, err := strconv.Atoi(spec[i+1:])
+			if err != nil || n < 1 || n > 9999 || spec[i+1] == '+' {
+				return templatePart{}, fmt.Errorf("invalid prefix modifier in %v", spec)
+			}
+			v.prefix, spec = n, spec[:i]
+		}
+		if !validVarname(spec) {
+			return templatePart{}, fmt.Errorf("invalid variable name %q", spec)
+		}
+		v.name = spec
+		part.vars = append(part.vars, v)
+	}
+	return part, nil
+}
+
+// validVarname reports whether s is a varname of RFC 6570: letters, digits, "_" and escapes, with single dots between them.
+func validVarname(s string) bool {
+	if s == "" || s[0] == '.' || s[len(s)-1] == '.' || strings.Contains(s, "..") {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '_' || c == '.' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9':
+		case c == '%' && i+2 < len(s) && ishex(s[i+1]) && ishex(s[i+2]):
+			i += 2
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func ishex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+// String returns the source text of the template.
+func (t *URITemplate) String() string {
+	return t.raw
+}
+
+// Expand expands the template with the fields of src, which is a structure or a pointer to one.
+// Values are formatted as in Pattern.Build.
+func (t *URITemplate) Expand(src interface{}) (string, error) {
+	v := reflect.Indirect(reflect.ValueOf(src))
+	if v.Kind() != reflect.Struct {
+		return "", errors.New("argument not a struct")
+	}
+	fields := fieldMapper.TypeMap(v.Type())
+
+	var b strings.Builder
+	for _, part := range t.parts {
+		if part.op == nil {
+			b.WriteString(encodeTemplate(part.literal, true))
+			continue
+		}
+
+		first := true
+		for _, tv := range part.vars {
+			fi, ok := fields.Names[tv.name]
+			if !ok {
+				continue
+			}
+			f, ok := fieldByIndexes(v, fi.Index)
+			if !ok {
+				continue
+			}
+			value, err := templateValueOf(f)
+			if err != nil {
+				return "", fmt.Errorf("URI template(%v): variable %v: %w", t.raw, tv.name, err)
+			}
+			if value == nil {
+				continue
+			}
+
+			if first {
+				b.WriteString(part.op.first)
+				first = false
+			} else {
+				b.WriteString(part.op.sep)
+			}
+			if err := part.op.expand(&b, tv, value); err != nil {
+				return "", fmt.Errorf("URI template(%v): variable %v: %w", t.raw, tv.name, err)
+			}
+		}
+	}
+	return b.String(), nil
+}
+
+// templateValue is a defined value of a variable: a string, a list or an associative array.
+type templateValue struct {
+	items []string
+	// list is true for a list, and assoc for an associative array, whose items are keys and values in turn.
+	list, assoc bool
+}
+
+// templateValueOf returns the value of a field, or nil if it is undefined.
+func templateValueOf(f reflect.Value) (*templateValue, error) {
+	if f.IsZero() {
+		return nil, nil
+	}
+	if f.Kind() == reflect.Ptr {
+		f = f.Elem()
+	}
+	switch f.Kind() {
+	case reflect.Slice, reflect.Array:
+		if f.Len() == 0 {
+			return nil, nil
+		}
+		value := &templateValue{list: true}
+		for i := 0; i < f.Len(); i++ {
+			item, err := formatValue(f.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			value.items = append(value.items, item)
+		}
+		return value, nil
+	case reflect.Map:
+		if f.Len() == 0 {
+			return nil, nil
+		}
+		value := &templateValue{assoc: true}
+		keys := make([]string, 0, f.Len())
+		values := make(map[string]string, f.Len())
+		iter := f.MapRange()
+		for iter.Next() {
+			key, err := formatValue(iter.Key())
+			if err != nil {
+				return nil, err
+			}
+			if values[key], err = formatValue(iter.Value()); err != nil {
+				return nil, err
+			}
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			value.items = append(value.items, key, values[key])
+		}
+		return value, nil
+	}
+
+	item, err := formatValue(f)
+	if err != nil {
+		return nil, err
+	}
+	return &templateValue{items: []string{item}}, nil
+}
+
+// expand writes a defined variable of an expression, after the first or sep string of the operator.
+func (op *templateOperator) expand(b *strings.Builder, tv templateVar, value *templateValue) error {
+	if !value.list && !value.assoc {
+		s := value.items[0]
+		if tv.prefix > 0 && utf8.RuneCountInString(s) > tv.prefix {
+			s = string([]rune(s)[:tv.prefix])
+		}
+		op.writeName(b, tv.name, s)
+		b.WriteString(encodeTemplate(s, op.reserved))
+		return nil
+	}
+	if tv.prefix > 0 {
+		return errors.New("a prefix modifier cannot be applied to a list or an associative array")
+	}
+
+	if !tv.explode {
+		if op.named {
+			b.WriteString(tv.name + "=")
+		}
+		for i, item := range value.items {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(encodeTemplate(item, op.reserved))
+		}
+		return nil
+	}
+
+	if value.list {
+		for i, item := range value.items {
+			if i > 0 {
+				b.WriteString(op.sep)
+			}
+			op.writeName(b, tv.name, item)
+			b.WriteString(encodeTemplate(item, op.reserved))
+		}
+		return nil
+	}
+	for i := 0; i < len(value.items); i += 2 {
+		if i > 0 {
+			b.WriteString(op.sep)
+		}
+		b.WriteString(encodeTemplate(value.items[i], op.reserved))
+		if op.named && value.items[i+1] == "" {
+			b.WriteString(op.ifEmpty)
+			continue
+		}
+		b.WriteString("=" + encodeTemplate(value.items[i+1], op.reserved))
+	}
+	return nil
+}
+
+// writeName writes the name of a variable for a named operator.
+func (op *templateOperator) writeName(b *strings.Builder, name, value string) {
+	if !op.named {
+		return
+	}
+	b.WriteString(name)
+	if value == "" {
+		b.WriteString(op.ifEmpty)
+	} else {
+		b.WriteByte('=')
+	}
+}
+
+const (
+	templateUnreserved = "-._~"
+	templateReserved   = ":/?#[]@!$&'()*+,;="
+)
+
+// encodeTemplate percent-encodes the characters of s that are not unreserved,
+// or, if reserved is true, that are neither unreserved nor reserved nor part of an escape.
+func encodeTemplate(s string, reserved bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || strings.IndexByte(templateUnreserved, c) >= 0:
+		case reserved && strings.IndexByte(templateReserved, c) >= 0:
+		case reserved && c == '%' && i+2 < len(s) && ishex(s[i+1]) && ishex(s[i+2]):
+		default:
+			_, _ = fmt.Fprintf(&b, "%%%02X", c)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// checkReversible returns why the expansions of the template cannot be mapped back into variables, or nil.
+func (t *URITemplate) checkReversible() error {
+	var prev *templatePart
+	for i := range t.parts {
+		part := &t.parts[i]
+		if part.op == nil {
+			prev = nil
+			continue
+		}
+		for j, tv := range part.vars {
+			if tv.prefix > 0 {
+				return fmt.Errorf("URI template(%v) is not reversible: the prefix modifier of %v drops part of the value", t.raw, tv.name)
+			}
+			if part.op.reserved && (len(part.vars) > 1 || tv.explode) {
+				return fmt.Errorf("URI template(%v) is not reversible: the values of a reserved expansion cannot be told apart", t.raw)
+			}
+			if !part.op.named && tv.explode && j != len(part.vars)-1 {
+				return fmt.Errorf("URI template(%v) is not reversible: the exploded variable %v is followed by other variables", t.raw, tv.name)
+			}
+		}
+		// An expression that directly follows another one must start with a character the other cannot contain.
+		if prev != nil && (part.op.first == "" || part.op.first == "." || prev.op.reserved) {
+			return fmt.Errorf("URI template(%v) is not reversible: an expression cannot be told apart from the one before it", t.raw)
+		}
+		prev = part
+	}
+	return nil
+}
+
+// regexp returns the regular expression that matches the expansions of the template,
+// with a group for each expression.
+func (t *URITemplate) regexp() string {
+	const escape = `%[0-9A-Fa-f]{2}`
+	var b strings.Builder
+	b.WriteString("^")
+	for _, part := range t.parts {
+		if part.op == nil {
+			b.WriteString(regexp.QuoteMeta(part.literal))
+			continue
+		}
+
+		chars := `A-Za-z0-9\-._~`
+		if part.op.reserved {
+			chars += regexp.QuoteMeta(templateReserved)
+		} else {
+			chars += ","
+		}
+		if part.op.sep == "." {
+			chars = strings.Replace(chars, ".", "", 1)
+		}
+		value := `(?:[` + chars + `]|` + escape + `)*`
+		item := value
+		if part.op.named {
+			item = `(?:[A-Za-z0-9_.]|` + escape + `)+(?:=` + value + `)?`
+		}
+		items := item + `(?:` + regexp.QuoteMeta(part.op.sep) + item + `)*`
+		if part.op.first != "" {
+			items = `(?:` + regexp.QuoteMeta(part.op.first) + items + `)?`
+		}
+		b.WriteString("(" + items + ")")
+	}
+	b.WriteString("$")
+	return b.String()
+}
+
+// Mapping maps the variables of uri, an expansion of the template, into dest.
+// Values are assigned to the variables of an expression without names from left to right,
+// and by name for the operators ";", "?" and "&", in which case the names may come in any order
+// and names of no variable are ignored, unless an exploded variable is mapped into a map.
+// It fails if the template is not reversible: if it uses prefix modifiers, reserved expansions of several values,
+// or expressions that cannot be told apart.
+func (t *URITemplate) Mapping(uri string, dest interface{}) error {
+	if t.irreversible != nil {
+		return t.irreversible
+	}
+	m := t.re.FindStringSubmatch(uri)
+	if m == nil {
+		return &MismatchError{Pattern: t.raw, Path: uri}
+	}
+
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr {
+		return errors.New("must pass a pointer, not a value, to dest")
+	}
+	if v.IsNil() {
+		return errors.New("must pass non-nil pointer to dest")
+	}
+	if reflect.Indirect(v).Kind() != reflect.Struct {
+		return errors.New("argument not a struct")
+	}
+
+	// Named expressions with the same separator, such as "{?page}{&per_page}", share their items,
+	// since the items of one may be captured by the group of another.
+	items := make([][]string, len(t.parts))
+	named := make(map[string][]string)
+	group := 0
+	for i, part := range t.parts {
+		if part.op == nil {
+			continue
+		}
+		group++
+		if m[group] == "" {
+			continue
+		}
+		items[i] = strings.Split(strings.TrimPrefix(m[group], part.op.first), part.op.sep)
+		if part.op.named {
+			named[part.op.sep] = append(named[part.op.sep], items[i]...)
+		}
+	}
+	for i, part := range t.parts {
+		if part.op == nil {
+			continue
+		}
+		if part.op.named {
+			items[i] = named[part.op.sep]
+		}
+		if items[i] == nil {
+			continue
+		}
+		if err := t.mapExpression(part, items[i], v, uri); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mapExpression maps the items of an expression into the fields of v.
+func (t *URITemplate) mapExpression(part templatePart, items []string, v reflect.Value, uri string) error {
+	if !part.op.named {
+		for j, tv := range part.vars {
+			if j >= len(items) {
+				break
+			}
+			if tv.explode {
+				return mapTemplateVar(v, tv, items[j:], part.op)
+			}
+			if err := mapTemplateVar(v, tv, items[j:j+1], part.op); err != nil {
+				return err
+			}
+		}
+		if n := len(part.vars); len(items) > n && !part.vars[n-1].explode {
+			return &MismatchError{Pattern: t.raw, Path: uri}
+		}
+		return nil
+	}
+
+	names := make(map[string]bool)
+	for _, tv := range part.vars {
+		names[tv.name] = true
+	}
+	for _, tv := range part.vars {
+		var values []string
+		for _, item := range items {
+			name, value, _ := strings.Cut(item, "=")
+			if name, err := url.PathUnescape(name); err != nil || name != tv.name {
+				continue
+			}
+			values = append(values, value)
+		}
+		if tv.explode && isMapField(v, tv.name) {
+			// The items of an exploded associative array are named after their keys.
+			values = nil
+			for _, item := range items {
+				if name, _, _ := strings.Cut(item, "="); !names[name] {
+					values = append(values, item)
+				}
+			}
+		}
+		if len(values) == 0 {
+			continue
+		}
+		if !tv.explode {
+			values = values[:1]
+		}
+		if err := mapTemplateVar(v, tv, values, part.op); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isMapField(v reflect.Value, name string) bool {
+	fi, ok := fieldMapper.TypeMap(reflect.Indirect(v).Type()).Names[name]
+	return ok && reflectx.Deref(fi.Field.Type).Kind() == reflect.Map
+}
+
+// mapTemplateVar assigns the items of a variable to its field: each item to an element of a slice,
+// or the keys and values of an associative array to a map. The items of a variable that is not exploded
+// are separated by commas. Fields that the template does not name are left as they are.
+func mapTemplateVar(v reflect.Value, tv templateVar, items []string, op *templateOperator) error {
+	traversal := fieldMapper.TraversalsByName(v.Type(), []string{tv.name})[0]
+	if len(traversal) == 0 {
+		return nil
+	}
+	f := reflectx.FieldByIndexes(v, traversal)
+	raw := strings.Join(items, op.sep)
+	kind := reflect.Indirect(f).Kind()
+	if _, ok := f.Addr().Interface().(Parser); ok {
+		kind = reflect.String
+	}
+
+	if kind == reflect.Slice || kind == reflect.Map {
+		if !tv.explode {
+			items = strings.Split(items[0], ",")
+		}
+	} else if len(items) != 1 {
+		return &BindError{Name: tv.name, Value: raw, Err: errors.New("several values for a single field")}
+	}
+
+	var values []string
+	for _, item := range items {
+		if kind == reflect.Map && tv.explode {
+			key, value, _ := strings.Cut(item, "=")
+			values = append(values, key, value)
+		} else {
+			values = append(values, item)
+		}
+	}
+	for i, value := range values {
+		decoded, err := url.PathUnescape(value)
+		if err != nil {
+			return &BindError{Name: tv.name, Value: raw, Err: err}
+		}
+		values[i] = decoded
+	}
+
+	switch kind {
+	case reflect.Slice:
+		f = reflect.Indirect(f)
+		s := reflect.MakeSlice(f.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := convertAssign(value, s.Index(i).Addr().Interface()); err != nil {
+				return &BindError{Name: tv.name, Value: raw, Err: err}
+			}
+		}
+		f.Set(s)
+	case reflect.Map:
+		f = reflect.Indirect(f)
+		if len(values)%2 != 0 {
+			return &BindError{Name: tv.name, Value: raw, Err: errors.New("odd number of keys and values")}
+		}
+		for i := 0; i < len(values); i += 2 {
+			key := reflect.New(f.Type().Key())
+			elem := reflect.New(f.Type().Elem())
+			if err := convertAssign(values[i], key.Interface()); err != nil {
+				return &BindError{Name: tv.name, Value: raw, Err: err}
+			}
+			if err := convertAssign(values[i+1], elem.Interface()); err != nil {
+				return &BindError{Name: tv.name, Value: raw, Err: err}
+			}
+			f.SetMapIndex(key.Elem(), elem.Elem())
+		}
+	default:
+		if err := convertAssign(values[0], f.Addr().Interface()); err != nil {
+			return &BindError{Name: tv.name, Value: raw, Err: err}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,273 @@
+package path_mapper
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TemplateVars holds the variables of the examples in RFC 6570, section 3.2.
+type TemplateVars struct {
+	Var   string
+	Hello string
+	Half  string
+	Path  string
+	List  []string
+	Keys  map[string]string
+	X     int
+	Y     int
+	Who   *string
+}
+
+func TestParseURITemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		success  bool
+	}{
+		{name: "Level 4", template: "/repos{/owner,repo}{?page,per_page}{&keys*}{#frag:3}", success: true},
+		{name: "Dotted and escaped names", template: "{a.b,c%20d}", success: true},
+		{name: "Unclosed expression", template: "/repos{/owner", success: false},
+		{name: "Unexpected brace", template: "/repos}", success: false},
+		{name: "Empty expression", template: "/repos{}", success: false},
+		{name: "Reserved operator", template: "{=x}", success: false},
+		{name: "Invalid prefix", template: "{x:0}", success: false},
+		{name: "Invalid name", template: "{a..b}", success: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseURITemplate(tt.template); (err == nil) != tt.success {
+				t.Errorf("ParseURITemplate() return (%v), which is not what we want.", err)
+			}
+		})
+	}
+}
+
+func TestURITemplate_Expand(t *testing.T) {
+	vars := TemplateVars{
+		Var:   "value",
+		Hello: "Hello World!",
+		Half:  "50%",
+		Path:  "/foo/bar",
+		List:  []string{"red", "green", "blue"},
+		Keys:  map[string]string{"semi": ";", "dot": ".", "comma": ","},
+		X:     1024,
+		Y:     768,
+	}
+
+	// Associative arrays are expanded in the order of their keys.
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{"{var}", "value"},
+		{"{hello}", "Hello%20World%21"},
+		{"{half}", "50%25"},
+		{"{+var}", "value"},
+		{"{+hello}", "Hello%20World!"},
+		{"{+path}/here", "/foo/bar/here"},
+		{"here?ref={+path}", "here?ref=/foo/bar"},
+		{"X{#var}", "X#value"},
+		{"X{#hello}", "X#Hello%20World!"},
+		{"map?{x,y}", "map?1024,768"},
+		{"{x,hello,y}", "1024,Hello%20World%21,768"},
+		{"{+x,hello,y}", "1024,Hello%20World!,768"},
+		{"{+path,x}/here", "/foo/bar,1024/here"},
+		{"{#x,hello,y}", "#1024,Hello%20World!,768"},
+		{"X{.var}", "X.value"},
+		{"X{.x,y}", "X.1024.768"},
+		{"{/var}", "/value"},
+		{"{/var,x}/here", "/value/1024/here"},
+		{"{;x,y}", ";x=1024;y=768"},
+		{"{?x,y}", "?x=1024&y=768"},
+		{"?fixed=yes{&x}", "?fixed=yes&x=1024"},
+		{"{var:3}", "val"},
+		{"{var:30}", "value"},
+		{"{list}", "red,green,blue"},
+		{"{list*}", "red,green,blue"},
+		{"{keys}", "comma,%2C,dot,.,semi,%3B"},
+		{"{keys*}", "comma=%2C,dot=.,semi=%3B"},
+		{"{+path:6}/here", "/foo/b/here"},
+		{"{+list}", "red,green,blue"},
+		{"{+keys}", "comma,,,dot,.,semi,;"},
+		{"{+keys*}", "comma=,,dot=.,semi=;"},
+		{"{#path:6}/here", "#/foo/b/here"},
+		{"{#list*}", "#red,green,blue"},
+		{"X{.list}", "X.red,green,blue"},
+		{"X{.list*}", "X.red.green.blue"},
+		{"{/var:1,var}", "/v/value"},
+		{"{/list*,path:4}", "/red/green/blue/%2Ffoo"},
+		{"{/keys*}", "/comma=%2C/dot=./semi=%3B"},
+		{"{;hello:5}", ";hello=Hello"},
+		{"{;list*}", ";list=red;list=green;list=blue"},
+		{"{;keys*}", ";comma=%2C;dot=.;semi=%3B"},
+		{"{?var:3}", "?var=val"},
+		{"{?list}", "?list=red,green,blue"},
+		{"{?list*}", "?list=red&list=green&list=blue"},
+		{"{?keys}", "?keys=comma,%2C,dot,.,semi,%3B"},
+		{"{?keys*}", "?comma=%2C&dot=.&semi=%3B"},
+		{"{&var:3}", "&var=val"},
+		{"{&list*}", "&list=red&list=green&list=blue"},
+		{"{&keys*}", "&comma=%2C&dot=.&semi=%3B"},
+		{"{/who}", ""},
+		{"{?x,who,y,undefined}", "?x=1024&y=768"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			got, err := MustParseURITemplate(tt.template).Expand(vars)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Expand() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type RepoQuery struct {
+	Owner   string
+	Repo    string
+	Page    int
+	PerPage int `alias:"per_page"`
+}
+
+type SearchQuery struct {
+	Q       string
+	Tags    []int
+	Path    []string
+	Filters map[string]string
+}
+
+func TestURITemplate_Mapping(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		uri      string
+		dest     interface{}
+		want     interface{}
+		success  bool
+	}{
+		{
+			name:     "Path and query",
+			template: "/repos{/owner,repo}{?page,per_page}",
+			uri:      "/repos/KamikazeZirou/path-mapper?per_page=10&page=2",
+			dest:     &RepoQuery{},
+			want:     &RepoQuery{Owner: "KamikazeZirou", Repo: "path-mapper", Page: 2, PerPage: 10},
+			success:  true,
+		},
+		{
+			name:     "Undefined variables",
+			template: "/repos{/owner,repo}{?page,per_page}",
+			uri:      "/repos/KamikazeZirou",
+			dest:     &RepoQuery{},
+			want:     &RepoQuery{Owner: "KamikazeZirou"},
+			success:  true,
+		},
+		{
+			name:     "Unknown names are ignored",
+			template: "/repos{/owner,repo}{?page}",
+			uri:      "/repos/a/b?sort=asc&page=3",
+			dest:     &RepoQuery{},
+			want:     &RepoQuery{Owner: "a", Repo: "b", Page: 3},
+			success:  true,
+		},
+		{
+			name:     "Lists and decoding",
+			template: "/search{?q,tags}",
+			uri:      "/search?q=hello%20world&tags=1,2",
+			dest:     &SearchQuery{},
+			want:     &SearchQuery{Q: "hello world", Tags: []int{1, 2}},
+			success:  true,
+		},
+		{
+			name:     "Exploded list",
+			template: "/files{/path*}",
+			uri:      "/files/a/b%2Fc",
+			dest:     &SearchQuery{},
+			want:     &SearchQuery{Path: []string{"a", "b/c"}},
+			success:  true,
+		},
+		{
+			name:     "Exploded associative array",
+			template: "/search{?q,filters*}",
+			uri:      "/search?q=x&lang=go&sort=stars",
+			dest:     &SearchQuery{},
+			want:     &SearchQuery{Q: "x", Filters: map[string]string{"lang": "go", "sort": "stars"}},
+			success:  true,
+		},
+		{
+			name:     "Too many values",
+			template: "/repos{/owner,repo}",
+			uri:      "/repos/a/b/c",
+			dest:     &RepoQuery{},
+			success:  false,
+		},
+		{
+			name:     "Value does not fit the field",
+			template: "/repos{/owner}{?page}",
+			uri:      "/repos/a?page=first",
+			dest:     &RepoQuery{},
+			success:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := MustParseURITemplate(tt.template).Mapping(tt.uri, tt.dest)
+			if (err == nil) != tt.success {
+				t.Fatalf("Mapping() return (%v), which is not what we want.", err)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, tt.dest); diff != "" {
+				t.Errorf("Mapping() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestURITemplate_Mapping_Errors(t *testing.T) {
+	var mismatch *MismatchError
+	if err := MustParseURITemplate("/repos{/owner}").Mapping("/users/a", &RepoQuery{}); !errors.As(err, &mismatch) {
+		t.Errorf("Mapping() return (%v), want *MismatchError", err)
+	}
+
+	for _, template := range []string{"{owner:3}", "{+owner,repo}", "{owner}{repo}", "{owner}{.repo}", "{/path*,owner}"} {
+		err := MustParseURITemplate(template).Mapping("abc", &RepoQuery{})
+		if err == nil || errors.As(err, &mismatch) {
+			t.Errorf("Mapping() with template(%v) return (%v), want an error saying it is not reversible", template, err)
+		}
+	}
+}
+
+func TestURITemplate_RoundTrip(t *testing.T) {
+	src := RepoQuery{Owner: "John Doe", Repo: "a/b", Page: 2, PerPage: 100}
+	for _, template := range []string{
+		"/repos{/owner,repo}{?page,per_page}",
+		"/repos/{owner}/{repo}{?page}{&per_page}",
+		"/repos{/owner}{;repo}{#page}",
+		"{owner}/x{.repo}{?page,per_page}",
+	} {
+		tmpl := MustParseURITemplate(template)
+		uri, err := tmpl.Expand(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := RepoQuery{}
+		if err := tmpl.Mapping(uri, &got); err != nil {
+			t.Fatalf("Mapping(%v) with template(%v) return (%v), which is not what we want.", uri, template, err)
+		}
+		want := src
+		if template == "/repos{/owner}{;repo}{#page}" {
+			want.PerPage = 0
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("Mapping(%v) mismatch (-want +got):\n%s", uri, diff)
+		}
+	}
+}
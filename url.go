@@ -0,0 +1,231 @@
+package path_mapper
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// URLPattern is a compiled pattern of a whole URL, such as "https://{tenant}.example.com:{port}/{path...}".
+//
+// The scheme and the port are optional, and a URL pattern without them matches any scheme or any port.
+// Each of them is either literal or a placeholder. A URL without a port has the default port of its scheme,
+// 80 for http and ws and 443 for https and wss, and does not match a pattern with a port under other schemes.
+// The host is split into dot-separated labels, each of which is literal, a placeholder,
+// or a mix of text and placeholders such as "{tenant}-api";
+// the first label may be a catch-all such as "{subdomain...}" that captures one or more leading labels.
+// Hosts are compared without regard to ASCII case.
+// The path is a Pattern, or "/" if it is omitted, and the empty path of a URL is matched as "/".
+type URLPattern struct {
+	raw    string
+	scheme segment
+	labels []segment
+	port   segment
+	path   *Pattern
+}
+
+// CompileURL parses a URL pattern. opts apply to the path.
+func CompileURL(pattern string, opts ...Option) (*URLPattern, error) {
+	p := &URLPattern{raw: pattern}
+	rest := pattern
+	var err error
+	if i := strings.Index(rest, "://"); i >= 0 {
+		if p.scheme, err = parseURLPart(rest[:i]); err != nil {
+			return nil, fmt.Errorf("URL pattern(%v): %w", pattern, err)
+		}
+		rest = rest[i+len("://"):]
+	}
+
+	host, path := rest, "/"
+	if i := strings.IndexByte(rest, '/'); i >= 0 {
+		host, path = rest[:i], rest[i:]
+	}
+	if i := portColon(host); i >= 0 {
+		if p.port, err = parseURLPart(host[i+1:]); err != nil {
+			return nil, fmt.Errorf("URL pattern(%v): %w", pattern, err)
+		}
+		host = host[:i]
+	}
+	if host == "" {
+		return nil, fmt.Errorf("URL pattern(%v) has no host", pattern)
+	}
+
+	for i, label := range splitOutsideBraces(host, '.') {
+		s, err := parseURLPart(label)
+		if err != nil {
+			return nil, fmt.Errorf("URL pattern(%v): %w", pattern, err)
+		}
+		if s.kind == catchAllSegment && i != 0 {
+			return nil, fmt.Errorf("URL pattern(%v): catch-all %v must be the first label", pattern, label)
+		}
+		for _, name := range s.placeholders() {
+			if name == "" {
+				return nil, fmt.Errorf("URL pattern(%v): placeholder has no name", pattern)
+			}
+		}
+		p.labels = append(p.labels, s)
+	}
+	for _, s := range []segment{p.scheme, p.port} {
+		if s.kind == catchAllSegment || s.kind == mixedSegment || (s.kind == placeholderSegment && s.value == "") {
+			return nil, fmt.Errorf("URL pattern(%v): scheme and port must be literal or a named placeholder", pattern)
+		}
+	}
+
+	if p.path, err = Compile(path, opts...); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	for _, s := range append(append([]segment{p.scheme, p.port}, p.labels...), p.path.segments...) {
+		for _, name := range s.placeholders() {
+			if name == "" {
+				continue
+			}
+			if seen[name] {
+				return nil, fmt.Errorf("URL pattern(%v) uses placeholder %v more than once", pattern, name)
+			}
+			seen[name] = true
+		}
+	}
+	return p, nil
+}
+
+// MustCompileURL is like CompileURL but panics if the pattern cannot be parsed.
+func MustCompileURL(pattern string, opts ...Option) *URLPattern {
+	p, err := CompileURL(pattern, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// splitOutsideBraces splits s at each sep that is not inside braces.
+func splitOutsideBraces(s string, sep byte) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case sep:
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// portColon returns the index of the colon that separates the port from the host, or -1.
+// Colons inside braces belong to placeholders.
+func portColon(host string) int {
+	depth := 0
+	for i := len(host) - 1; i >= 0; i-- {
+		switch host[i] {
+		case '}':
+			depth++
+		case '{':
+			depth--
+		case ':':
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func parseURLPart(s string) (segment, error) {
+	if !strings.HasPrefix(s, "{") || closingBrace(s, 0) != len(s)-1 {
+		return parseSegment(s)
+	}
+	name := s[1 : len(s)-1]
+	if name == "" {
+		return segment{kind: placeholderSegment}, nil
+	}
+	return parsePlaceholder(name)
+}
+
+// defaultPorts are the ports of the schemes whose URLs may leave them out.
+var defaultPorts = map[string]string{"http": "80", "https": "443", "ws": "80", "wss": "443"}
+
+// hostOptions compare the literal text of host labels as hosts are compared.
+var hostOptions = options{literalCase: IgnoreASCIICase}
+
+// String returns the source text used to compile the pattern.
+func (p *URLPattern) String() string {
+	return p.raw
+}
+
+// Mapping maps the scheme, host, port and path of u into dest.
+func (p *URLPattern) Mapping(u *url.URL, dest interface{}) error {
+	var names, values []string
+	capture := func(s segment, value string) bool {
+		switch s.kind {
+		case literalSegment:
+			return asciiEqualFold(s.value, value)
+		case placeholderSegment, catchAllSegment:
+			if !s.allows(value) {
+				return false
+			}
+			names = append(names, s.value)
+			values = append(values, value)
+		case mixedSegment:
+			var captured []string
+			if !s.matchMixed(value, &hostOptions, func(v string) { captured = append(captured, v) }) {
+				return false
+			}
+			names = append(names, s.placeholders()...)
+			values = append(values, captured...)
+		}
+		return true
+	}
+
+	mismatch := &MismatchError{Pattern: p.raw, Path: u.String()}
+	if (p.scheme.kind != literalSegment || p.scheme.value != "") && !capture(p.scheme, u.Scheme) {
+		return mismatch
+	}
+
+	labels := strings.Split(u.Hostname(), ".")
+	fixed := p.labels
+	if p.labels[0].kind == catchAllSegment {
+		fixed = p.labels[1:]
+		if len(labels) <= len(fixed) {
+			return mismatch
+		}
+		n := len(labels) - len(fixed)
+		capture(p.labels[0], strings.Join(labels[:n], "."))
+		labels = labels[n:]
+	}
+	if len(labels) != len(fixed) {
+		return mismatch
+	}
+	for i, s := range fixed {
+		if !capture(s, labels[i]) {
+			return mismatch
+		}
+	}
+
+	if p.port.kind != literalSegment || p.port.value != "" {
+		port := u.Port()
+		if port == "" {
+			port = defaultPorts[strings.ToLower(u.Scheme)]
+		}
+		if port == "" || !capture(p.port, port) {
+			return mismatch
+		}
+	}
+
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	pathNames, pathValues, ok := p.path.match(path)
+	if !ok {
+		return mismatch
+	}
+	return bind(append(names, pathNames...), append(values, pathValues...), dest)
+}
@@ -0,0 +1,169 @@
+package path_mapper
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type TenantFile struct {
+	Scheme string
+	Tenant string
+	Port   int
+	Path   string
+}
+
+func TestURLPattern_Mapping(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		url     string
+		want    *TenantFile
+		success bool
+	}{
+		{
+			name:    "Host and path",
+			pattern: "{tenant}.example.com/{path...}",
+			url:     "https://acme.example.com/docs/README.md",
+			want:    &TenantFile{Tenant: "acme", Path: "docs/README.md"},
+			success: true,
+		},
+		{
+			name:    "Scheme, host, port and path",
+			pattern: "{scheme}://{tenant}.example.com:{port}/files/{path}",
+			url:     "http://acme.example.com:8080/files/John%20Doe",
+			want:    &TenantFile{Scheme: "http", Tenant: "acme", Port: 8080, Path: "John Doe"},
+			success: true,
+		},
+		{
+			name:    "Host is case-insensitive",
+			pattern: "https://{tenant}.example.com",
+			url:     "HTTPS://Acme.EXAMPLE.com",
+			want:    &TenantFile{Tenant: "Acme"},
+			success: true,
+		},
+		{
+			name:    "Catch-all label",
+			pattern: "{tenant...}.example.com",
+			url:     "https://eu.acme.example.com/",
+			want:    &TenantFile{Tenant: "eu.acme"},
+			success: true,
+		},
+		{
+			name:    "Catch-all label needs at least one label",
+			pattern: "{tenant...}.example.com",
+			url:     "https://example.com/",
+			success: false,
+		},
+		{
+			name:    "Label of text and a placeholder",
+			pattern: "{tenant}-API.example.com",
+			url:     "https://acme-api.example.com",
+			want:    &TenantFile{Tenant: "acme"},
+			success: true,
+		},
+		{
+			name:    "Label of text and a placeholder does not match",
+			pattern: "{tenant}-api.example.com",
+			url:     "https://acme.example.com",
+			success: false,
+		},
+		{
+			name:    "Default port of the scheme",
+			pattern: "{tenant}.example.com:{port}",
+			url:     "https://acme.example.com",
+			want:    &TenantFile{Tenant: "acme", Port: 443},
+			success: true,
+		},
+		{
+			name:    "Literal default port",
+			pattern: "{tenant}.example.com:80",
+			url:     "http://acme.example.com",
+			want:    &TenantFile{Tenant: "acme"},
+			success: true,
+		},
+		{
+			name:    "No port and no default port",
+			pattern: "{tenant}.example.com:{port}",
+			url:     "ftp://acme.example.com",
+			success: false,
+		},
+		{
+			name:    "Scheme does not match",
+			pattern: "https://{tenant}.example.com",
+			url:     "http://acme.example.com",
+			success: false,
+		},
+		{
+			name:    "Host does not match",
+			pattern: "{tenant}.example.com",
+			url:     "https://acme.example.org",
+			success: false,
+		},
+		{
+			name:    "Port does not match",
+			pattern: "{tenant}.example.com:443",
+			url:     "https://acme.example.com:8443",
+			success: false,
+		},
+		{
+			name:    "Path does not match",
+			pattern: "{tenant}.example.com",
+			url:     "https://acme.example.com/docs",
+			success: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse(tt.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := &TenantFile{}
+			err = MustCompileURL(tt.pattern).Mapping(u, got)
+			if (err == nil) != tt.success {
+				t.Fatalf("Mapping() return (%v), which is not what we want.", err)
+			}
+			var mismatch *MismatchError
+			if err != nil && !errors.As(err, &mismatch) {
+				t.Fatalf("Mapping() return (%v), which is not a mismatch.", err)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Mapping() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCompileURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		success bool
+	}{
+		{name: "Host only", pattern: "example.com", success: true},
+		{name: "No host", pattern: "https:///path", success: false},
+		{name: "Catch-all label in the middle", pattern: "www.{tenant...}.example.com", success: false},
+		{name: "Catch-all port", pattern: "example.com:{port...}", success: false},
+		{name: "Duplicate placeholder", pattern: "{tenant}.example.com/{tenant}", success: false},
+		{name: "Mixed label", pattern: "{tenant}-api.example.com", success: true},
+		{name: "Mixed label with an unnamed placeholder", pattern: "{}-api.example.com", success: false},
+		{name: "Mixed label with a catch-all", pattern: "{tenant...}-api.example.com", success: false},
+		{name: "Mixed port", pattern: "example.com:8{port}", success: false},
+		{name: "Unbalanced brace in a label", pattern: "{tenant.example.com", success: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := CompileURL(tt.pattern); (err == nil) != tt.success {
+				t.Errorf("CompileURL() return (%v), which is not what we want.", err)
+			}
+		})
+	}
+}
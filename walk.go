@@ -0,0 +1,124 @@
+package path_mapper
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+)
+
+// WalkMatches calls fn for every file in fsys whose path matches pattern,
+// with the placeholders of the path mapped into a new value of T, which must be a structure type.
+//
+// pattern is relative to the root of fsys, like "logs/{service}/{year}/{month}/{day}.log",
+// and paths are neither percent-decoded nor normalized.
+// Directories are visited only as far as the pattern can match: a literal segment is looked up directly
+// instead of reading its parent directory, and only a catch-all walks a whole subtree.
+// Files are visited in lexical order. Files whose values cannot be mapped into T are skipped.
+// If fn returns fs.SkipAll, the walk stops and WalkMatches returns nil; any other error stops the walk and is returned.
+func WalkMatches[T any](fsys fs.FS, pattern string, fn func(path string, v T) error) error {
+	p, err := compile(pattern, options{noDecoding: true})
+	if err != nil {
+		return err
+	}
+	if p.segments[0].kind == literalSegment && p.segments[0].value == "" {
+		return fmt.Errorf("pattern(%v): must be relative to the root of the file system", pattern)
+	}
+
+	w := &walker[T]{fsys: fsys, pattern: p, fn: fn}
+	if err := w.walk(".", 0); err != nil && !errors.Is(err, fs.SkipAll) {
+		return err
+	}
+	return nil
+}
+
+type walker[T any] struct {
+	fsys    fs.FS
+	pattern *Pattern
+	fn      func(path string, v T) error
+}
+
+// walk visits the entries of dir that match the i-th segment of the pattern.
+func (w *walker[T]) walk(dir string, i int) error {
+	s := &w.pattern.segments[i]
+	last := i == len(w.pattern.segments)-1
+
+	switch s.kind {
+	case literalSegment:
+		if s.value == "" {
+			return nil
+		}
+		path := joinPath(dir, s.value)
+		info, err := fs.Stat(w.fsys, path)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return w.next(path, info.IsDir(), i, last)
+	case catchAllSegment:
+		var stopped error
+		err := fs.WalkDir(w.fsys, dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			if err := w.visit(path); err != nil {
+				stopped = err
+				return fs.SkipAll
+			}
+			return nil
+		})
+		if stopped != nil {
+			return stopped
+		}
+		return err
+	}
+
+	entries, err := fs.ReadDir(w.fsys, dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if s.kind == placeholderSegment && !s.allows(name) ||
+			s.kind == mixedSegment && !s.matchMixed(name, &w.pattern.opts, func(string) {}) {
+			continue
+		}
+		if err := w.next(joinPath(dir, name), entry.IsDir(), i, last); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// next continues the walk below path, which matched the i-th segment of the pattern.
+func (w *walker[T]) next(path string, dir bool, i int, last bool) error {
+	switch {
+	case last && !dir:
+		return w.visit(path)
+	case !last && dir:
+		return w.walk(path, i+1)
+	}
+	return nil
+}
+
+func (w *walker[T]) visit(path string) error {
+	var v T
+	err := w.pattern.Mapping(path, &v)
+	var bindErr *BindError
+	var mismatchErr *MismatchError
+	if errors.As(err, &bindErr) || errors.As(err, &mismatchErr) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return w.fn(path, v)
+}
+
+func joinPath(dir, name string) string {
+	if dir == "." {
+		return name
+	}
+	return dir + "/" + name
+}
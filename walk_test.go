@@ -0,0 +1,148 @@
+package path_mapper
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type LogFile struct {
+	Service string
+	Year    int
+	Month   int
+	Day     int
+}
+
+type ArchiveFile struct {
+	Service string
+	Path    string
+}
+
+func TestWalkMatches(t *testing.T) {
+	fsys := fstest.MapFS{
+		"logs/api/2024/01/01.log":    {},
+		"logs/api/2024/01/02.log":    {},
+		"logs/api/2024/01/02.txt":    {},
+		"logs/api/2024/02/01.log":    {},
+		"logs/api/2024/02/latest":    {},
+		"logs/api/2024/xx/01.log":    {},
+		"logs/web/2023/12/31.log":    {},
+		"logs/web/2023/12/31.log.1":  {},
+		"logs/web/README.md":         {},
+		"archive/api/2020/01.tar.gz": {},
+		"archive/api/old/2019.gz":    {},
+	}
+
+	t.Run("Placeholders and mixed segments", func(t *testing.T) {
+		var got []string
+		var values []LogFile
+		err := WalkMatches(fsys, "logs/{service}/{year}/{month}/{day}.log", func(path string, v LogFile) error {
+			got = append(got, path)
+			values = append(values, v)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []string{
+			"logs/api/2024/01/01.log",
+			"logs/api/2024/01/02.log",
+			"logs/api/2024/02/01.log",
+			"logs/web/2023/12/31.log",
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("WalkMatches() paths mismatch (-want +got):\n%s", diff)
+		}
+		wantValues := []LogFile{
+			{Service: "api", Year: 2024, Month: 1, Day: 1},
+			{Service: "api", Year: 2024, Month: 1, Day: 2},
+			{Service: "api", Year: 2024, Month: 2, Day: 1},
+			{Service: "web", Year: 2023, Month: 12, Day: 31},
+		}
+		if diff := cmp.Diff(wantValues, values); diff != "" {
+			t.Errorf("WalkMatches() values mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Catch-all", func(t *testing.T) {
+		var got []ArchiveFile
+		err := WalkMatches(fsys, "archive/{service}/{path...}", func(path string, v ArchiveFile) error {
+			got = append(got, v)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []ArchiveFile{
+			{Service: "api", Path: "2020/01.tar.gz"},
+			{Service: "api", Path: "old/2019.gz"},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("WalkMatches() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("SkipAll stops the walk", func(t *testing.T) {
+		var got []string
+		err := WalkMatches(fsys, "{...}", func(path string, v struct{}) error {
+			got = append(got, path)
+			return fs.SkipAll
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 {
+			t.Errorf("WalkMatches() visited %v, want a single file", got)
+		}
+	})
+
+	t.Run("Errors are returned", func(t *testing.T) {
+		errStop := errors.New("stop")
+		err := WalkMatches(fsys, "logs/{service}/{year}/{month}/{day}.log", func(path string, v LogFile) error {
+			return errStop
+		})
+		if !errors.Is(err, errStop) {
+			t.Errorf("WalkMatches() return (%v), which is not what we want.", err)
+		}
+	})
+
+	t.Run("Literal segments are looked up directly", func(t *testing.T) {
+		fsys := openCounter{FS: fsys, dirs: make(map[string]int)}
+		err := WalkMatches(fsys, "logs/api/2024/{month}/{day}.log", func(path string, v LogFile) error {
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, dir := range []string{".", "logs", "logs/api", "archive"} {
+			if fsys.dirs[dir] != 0 {
+				t.Errorf("WalkMatches() read %v, which is not what we want.", dir)
+			}
+		}
+	})
+
+	t.Run("Rooted pattern", func(t *testing.T) {
+		err := WalkMatches(fsys, "/logs/{service}", func(path string, v LogFile) error {
+			return nil
+		})
+		if err == nil {
+			t.Error("WalkMatches() return (nil), which is not what we want.")
+		}
+	})
+}
+
+// openCounter counts the directories read through it.
+type openCounter struct {
+	fs.FS
+	dirs map[string]int
+}
+
+func (c openCounter) ReadDir(name string) ([]fs.DirEntry, error) {
+	c.dirs[name]++
+	return fs.ReadDir(c.FS, name)
+}